@@ -2,46 +2,99 @@
 Package main offers a demo utility for the zylog logger wrapper.
 
 Log entries with both caller (package, function, and line number) as well as
-without caller information are demonstrated.
+without caller information are demonstrated, for both logrus and slog.
 */
 package main
 
 import (
-	"fmt"
-	"runtime"
+	"log/slog"
 
+	"github.com/fatih/color"
+	"github.com/geomyidia/zylog"
 	logger "github.com/geomyidia/zylog/logger"
 	log "github.com/sirupsen/logrus"
 )
 
 // SetupLogger ...
 func SetupLogger() {
-	logger.SetupLogging(&logger.ZyLogOptions{
+	_, err := zylog.SetupLogging(&logger.ZyLogOptions{
+		Logger:       logger.LogRUs,
 		Colored:      true,
 		Level:        "trace",
 		Output:       "stdout",
 		ReportCaller: true,
 	})
+	if err != nil {
+		panic(err)
+	}
 }
 
 // SetupLoggerNoCaller ...
 func SetupLoggerNoCaller() {
-	logger.SetupLogging(&logger.ZyLogOptions{
+	_, err := zylog.SetupLogging(&logger.ZyLogOptions{
+		Logger:       logger.LogRUs,
 		Colored:      true,
 		Level:        "trace",
 		Output:       "stdout",
 		ReportCaller: false,
 	})
+	if err != nil {
+		panic(err)
+	}
 }
 
-func printVersions() {
-	fmt.Printf("zylog version: %s\n", logger.VersionString())
-	fmt.Printf("Build: %s\n", logger.BuildString())
-	fmt.Printf("Go version: %s\n", runtime.Version())
+// SetupSlog ...
+func SetupSlog() *slog.Logger {
+	l, err := zylog.SetupLogging(&logger.ZyLogOptions{
+		Logger:          logger.Slog,
+		Colored:         true,
+		Level:           "trace",
+		Output:          "stdout",
+		ReportCaller:    true,
+		TimestampFormat: logger.TimestampRFC3339Milli,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// SetupSlogStyled ...
+func SetupSlogStyled() *slog.Logger {
+	colours := logger.DefaultColours()
+	colours.Fatal = logger.Colour{Fg: color.FgRed, Styles: []color.Attribute{color.Bold}}
+	l, err := zylog.SetupLogging(&logger.ZyLogOptions{
+		Logger:  logger.Slog,
+		Colored: true,
+		Colours: colours,
+		Level:   "trace",
+		Output:  "stdout",
+		// Keep the demo going after logging at FATAL.
+		ExitFunc: func(int) {},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// SetupSlogTheme ...
+func SetupSlogTheme(theme string) *slog.Logger {
+	l, err := zylog.SetupLogging(&logger.ZyLogOptions{
+		Logger:  logger.Slog,
+		Colored: true,
+		Theme:   theme,
+		Level:   "trace",
+		Output:  "stdout",
+	})
+	if err != nil {
+		panic(err)
+	}
+	return l
 }
 
 func main() {
-	printVersions()
+	zylog.PrintVersions()
 	SetupLogger()
 	log.Trace("This is trace")
 	log.Debug("This is debug")
@@ -57,4 +110,22 @@ func main() {
 	log.Info("This is info")
 	log.Warn("This is warn")
 	log.Error("This is error")
+	log.Info("The same formatting is available for slog (here with milliseconds):")
+	l := SetupSlog()
+	zylog.Trace(l, "This is trace")
+	l.Debug("This is debug")
+	l.Info("This is info", "answer", 42)
+	l.Warn("This is warn", zylog.Bytes("size", 1<<20))
+	l.Error("This is error", slog.Group("req", "method", "GET", "status", 500))
+	l.Info("Colours can have styles as well, such as a bold red FATAL:")
+	l = SetupSlogStyled()
+	zylog.Fatal(l, "This is fatal (though the demo carries on)")
+	for _, theme := range logger.ColourPresets() {
+		l.Info("Theme " + theme + ":")
+		l = SetupSlogTheme(theme)
+		l.Debug("This is debug")
+		l.Info("This is info")
+		l.Warn("This is warn")
+		l.Error("This is error")
+	}
 }
@@ -0,0 +1,219 @@
+// Package apitest snapshots the exported API of a package, so that a test can
+// catch changes to it which would break callers.
+package apitest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update-api", false, "rewrite the API snapshots in testdata")
+
+// Check compares the exported API of the package in dir with the snapshot
+// in the file golden, failing t if they differ. With the -update-api flag,
+// the snapshot is rewritten instead, for when an API change is intended.
+func Check(t *testing.T, dir, golden string) {
+	t.Helper()
+	api, err := API(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, []byte(api), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("%v (run go test with -update-api to create it)", err)
+	}
+	if api == string(want) {
+		return
+	}
+	got := lines(api)
+	wanted := lines(string(want))
+	for line := range wanted {
+		if !got[line] {
+			t.Errorf("removed or changed: %s", line)
+		}
+	}
+	for line := range got {
+		if !wanted[line] {
+			t.Errorf("added: %s", line)
+		}
+	}
+	t.Log("if the change is intended, run go test with -update-api")
+}
+
+func lines(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, line := range strings.Split(s, "\n") {
+		if line != "" {
+			set[line] = true
+		}
+	}
+	return set
+}
+
+// API returns the exported API of the package in dir, as built for the
+// current platform: a sorted list of its exported constants, variables,
+// types, struct fields, interface methods, functions, and methods, with
+// their types, one per line.
+func API(dir string) (string, error) {
+	pkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return "", err
+	}
+	fset := token.NewFileSet()
+	var decls []string
+	for _, name := range pkg.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return "", err
+		}
+		for _, decl := range f.Decls {
+			decls = append(decls, describe(fset, decl)...)
+		}
+	}
+	sort.Strings(decls)
+	return strings.Join(decls, "\n") + "\n", nil
+}
+
+// Describe the exported parts of a declaration.
+func describe(fset *token.FileSet, decl ast.Decl) []string {
+	var out []string
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if !d.Name.IsExported() {
+			break
+		}
+		name := d.Name.Name
+		if d.Recv != nil {
+			recv := typeString(fset, d.Recv.List[0].Type)
+			if !ast.IsExported(strings.TrimLeft(recv, "*")) {
+				break
+			}
+			name = "(" + recv + ") " + name
+		}
+		out = append(out, "func "+name+signature(fset, d.Type))
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.ValueSpec:
+				for _, n := range s.Names {
+					if !n.IsExported() {
+						continue
+					}
+					line := d.Tok.String() + " " + n.Name
+					if s.Type != nil {
+						line += " " + typeString(fset, s.Type)
+					}
+					out = append(out, line)
+				}
+			case *ast.TypeSpec:
+				if s.Name.IsExported() {
+					out = append(out, describeType(fset, s)...)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// Describe an exported type, with its exported fields or methods.
+func describeType(fset *token.FileSet, s *ast.TypeSpec) []string {
+	name := s.Name.Name
+	switch t := s.Type.(type) {
+	case *ast.StructType:
+		out := []string{"type " + name + " struct"}
+		for _, field := range t.Fields.List {
+			typ := typeString(fset, field.Type)
+			if len(field.Names) == 0 {
+				// An embedded field.
+				if ast.IsExported(strings.TrimLeft(typ[strings.LastIndex(typ, ".")+1:], "*")) {
+					out = append(out, "field "+name+" embeds "+typ)
+				}
+			}
+			for _, n := range field.Names {
+				if n.IsExported() {
+					out = append(out, "field "+name+"."+n.Name+" "+typ)
+				}
+			}
+		}
+		return out
+	case *ast.InterfaceType:
+		out := []string{"type " + name + " interface"}
+		for _, m := range t.Methods.List {
+			if len(m.Names) == 0 {
+				out = append(out, "method "+name+" embeds "+typeString(fset, m.Type))
+			}
+			for _, n := range m.Names {
+				if ft, ok := m.Type.(*ast.FuncType); ok && n.IsExported() {
+					out = append(out, "method "+name+"."+n.Name+signature(fset, ft))
+				}
+			}
+		}
+		return out
+	}
+	sep := " "
+	if s.Assign.IsValid() {
+		sep = " = "
+	}
+	return []string{"type " + name + sep + typeString(fset, s.Type)}
+}
+
+// The parameter and result types of a function, without their names, which
+// callers don't depend on.
+func signature(fset *token.FileSet, ft *ast.FuncType) string {
+	s := "(" + fieldTypes(fset, ft.Params) + ")"
+	if ft.Results == nil {
+		return s
+	}
+	results := fieldTypes(fset, ft.Results)
+	if len(ft.Results.List) == 1 && len(ft.Results.List[0].Names) <= 1 {
+		return s + " " + results
+	}
+	return s + " (" + results + ")"
+}
+
+func fieldTypes(fset *token.FileSet, fields *ast.FieldList) string {
+	if fields == nil {
+		return ""
+	}
+	var types []string
+	for _, field := range fields.List {
+		typ := typeString(fset, field.Type)
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			types = append(types, typ)
+		}
+	}
+	return strings.Join(types, ", ")
+}
+
+func typeString(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, expr); err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	// Collapse struct and interface literals spread over several lines.
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
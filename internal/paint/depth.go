@@ -0,0 +1,173 @@
+package paint
+
+import (
+	"os"
+	"strings"
+)
+
+// Depth is how many colours a terminal can show.
+type Depth int
+
+// The colour depths, from the sixteen basic ANSI colours up.
+const (
+	Depth16 Depth = iota
+	Depth256
+	DepthTrueColour
+)
+
+// DetectDepth returns the colour depth the environment advertises: true
+// colour if COLORTERM is "truecolor" or "24bit", 256 colours if TERM names a
+// 256-colour terminal (e.g. "xterm-256color"), and otherwise the basic
+// sixteen.
+func DetectDepth() Depth {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return DepthTrueColour
+	}
+	if strings.Contains(os.Getenv("TERM"), "256") {
+		return Depth256
+	}
+	return Depth16
+}
+
+// Depth returns the colour depth the Painter paints with.
+func (p Painter) Depth() Depth {
+	return p.depth
+}
+
+// FgRGB returns the colours which select the given 24-bit foreground colour,
+// or the nearest the Painter's depth allows.
+func (p Painter) FgRGB(r, g, b uint8) []Colour {
+	switch p.depth {
+	case DepthTrueColour:
+		return RGB(r, g, b)
+	case Depth256:
+		return []Colour{38, 5, Colour(nearest256(r, g, b))}
+	}
+	return []Colour{basicFg(nearestBasic(r, g, b))}
+}
+
+// BgRGB returns the colours which select the given 24-bit background colour,
+// or the nearest the Painter's depth allows.
+func (p Painter) BgRGB(r, g, b uint8) []Colour {
+	switch p.depth {
+	case DepthTrueColour:
+		return BgRGB(r, g, b)
+	case Depth256:
+		return []Colour{48, 5, Colour(nearest256(r, g, b))}
+	}
+	return []Colour{basicFg(nearestBasic(r, g, b)) + 10}
+}
+
+// Fg256 returns the colours which select the given colour of the 256-colour
+// palette as the foreground, or the nearest basic colour if the Painter's
+// depth doesn't allow it.
+func (p Painter) Fg256(n uint8) []Colour {
+	if p.depth >= Depth256 {
+		return []Colour{38, 5, Colour(n)}
+	}
+	r, g, b := palette256(n)
+	return []Colour{basicFg(nearestBasic(r, g, b))}
+}
+
+// Bg256 returns the colours which select the given colour of the 256-colour
+// palette as the background, or the nearest basic colour if the Painter's
+// depth doesn't allow it.
+func (p Painter) Bg256(n uint8) []Colour {
+	if p.depth >= Depth256 {
+		return []Colour{48, 5, Colour(n)}
+	}
+	r, g, b := palette256(n)
+	return []Colour{basicFg(nearestBasic(r, g, b)) + 10}
+}
+
+// The sixteen basic colours, as xterm shows them by default.
+var basicRGB = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// The levels of each component in the 6×6×6 colour cube of the 256-colour
+// palette.
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// The foreground code of the basic colour with the given index: 30 to 37,
+// then 90 to 97 for the bright ones.
+func basicFg(i int) Colour {
+	if i < 8 {
+		return Colour(30 + i)
+	}
+	return Colour(90 + i - 8)
+}
+
+// The components of a colour of the 256-colour palette.
+func palette256(n uint8) (r, g, b uint8) {
+	switch {
+	case n < 16:
+		c := basicRGB[n]
+		return c[0], c[1], c[2]
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]
+	}
+	grey := 8 + 10*(n-232)
+	return grey, grey, grey
+}
+
+// The index of the basic colour nearest to the given one.
+func nearestBasic(r, g, b uint8) int {
+	best, bestDist := 0, -1
+	for i, c := range basicRGB {
+		if d := distance(r, g, b, c[0], c[1], c[2]); bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// The colour of the 256-colour palette nearest to the given one, from its
+// colour cube or its greys.
+func nearest256(r, g, b uint8) uint8 {
+	cube := 16 + 36*nearestLevel(r) + 6*nearestLevel(g) + nearestLevel(b)
+	cr, cg, cb := palette256(uint8(cube))
+	// The greys run from 8 to 238 in steps of 10.
+	avg := (int(r) + int(g) + int(b)) / 3
+	step := (avg - 8 + 5) / 10
+	if step < 0 {
+		step = 0
+	} else if step > 23 {
+		step = 23
+	}
+	grey := uint8(232 + step)
+	gr, gg, gb := palette256(grey)
+	if distance(r, g, b, gr, gg, gb) < distance(r, g, b, cr, cg, cb) {
+		return grey
+	}
+	return uint8(cube)
+}
+
+// The index of the cube level nearest to a component.
+func nearestLevel(v uint8) int {
+	best := 0
+	for i, l := range cubeLevels {
+		if absDiff(v, l) < absDiff(v, cubeLevels[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// The squared distance between two colours.
+func distance(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr, dg, db := absDiff(r1, r2), absDiff(g1, g2), absDiff(b1, b2)
+	return dr*dr + dg*dg + db*db
+}
+
+func absDiff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
@@ -0,0 +1,73 @@
+/*
+Package paint is the one place in zylog where colour escape codes are
+produced, and the only package that imports fatih/color.
+
+A Painter only ever returns strings; it never writes to an output, and it
+never consults fatih/color's global NoColor setting. Whether colour is used is
+decided per Painter, which lets several loggers with different colour settings
+coexist in one process.
+*/
+package paint
+
+import (
+	"io"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// Colour is an attribute understood by a Painter: a foreground or background
+// colour.
+type Colour = color.Attribute
+
+// RGB returns the colours which select a 24-bit foreground colour.
+func RGB(r, g, b uint8) []Colour {
+	return []Colour{38, 2, Colour(r), Colour(g), Colour(b)}
+}
+
+// BgRGB returns the colours which select a 24-bit background colour.
+func BgRGB(r, g, b uint8) []Colour {
+	return []Colour{48, 2, Colour(r), Colour(g), Colour(b)}
+}
+
+// Painter wraps strings in colour escape codes, if it is enabled.
+type Painter struct {
+	enabled bool
+	depth   Depth
+}
+
+// New creates a Painter, for the colour depth the environment advertises
+// (see DetectDepth); a disabled Painter returns strings unchanged.
+func New(enabled bool) Painter {
+	p := Painter{enabled: enabled}
+	if enabled {
+		p.depth = DetectDepth()
+	}
+	return p
+}
+
+// Enabled reports whether the Painter produces colour escape codes.
+func (p Painter) Enabled() bool {
+	return p.enabled
+}
+
+// Paint returns s wrapped in the escape codes for the given colours; with no
+// colours, s is returned unchanged.
+func (p Painter) Paint(s string, cs ...Colour) string {
+	if !p.enabled || len(cs) == 0 {
+		return s
+	}
+	col := color.New(cs...)
+	col.EnableColor()
+	return col.Sprint(s)
+}
+
+// IsTerminal reports whether the writer is a file connected to a terminal.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
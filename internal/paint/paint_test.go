@@ -0,0 +1,46 @@
+package paint
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestPaint(t *testing.T) {
+	tests := []struct {
+		name string
+		cs   []Colour
+		want string
+	}{
+		{name: "none", want: "text"},
+		{name: "foreground", cs: []Colour{color.FgRed}, want: "\x1b[31mtext\x1b[0m"},
+		{name: "background", cs: []Colour{color.BgBlue}, want: "\x1b[44mtext\x1b[0m"},
+		{name: "style", cs: []Colour{color.Bold}, want: "\x1b[1mtext\x1b[0m"},
+		{name: "combined", cs: []Colour{color.FgHiWhite, color.BgRed, color.Underline},
+			want: "\x1b[97;41;4mtext\x1b[0m"},
+		{name: "RGB", cs: RGB(255, 136, 0), want: "\x1b[38;2;255;136;0mtext\x1b[0m"},
+		{name: "background RGB", cs: BgRGB(32, 32, 32), want: "\x1b[48;2;32;32;32mtext\x1b[0m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(true).Paint("text", tt.cs...); got != tt.want {
+				t.Errorf("Paint = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaintDisabled(t *testing.T) {
+	old := color.NoColor
+	defer func() { color.NoColor = old }()
+	// fatih/color's global setting is not consulted either way.
+	for _, noColor := range []bool{false, true} {
+		color.NoColor = noColor
+		if got := New(false).Paint("text", color.FgRed, color.Bold); got != "text" {
+			t.Errorf("disabled Paint = %q, want text unchanged", got)
+		}
+		if got := New(true).Paint("text", color.FgRed); got != "\x1b[31mtext\x1b[0m" {
+			t.Errorf("enabled Paint with NoColor %v = %q", noColor, got)
+		}
+	}
+}
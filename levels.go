@@ -0,0 +1,53 @@
+package zylog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/geomyidia/zylog/logger"
+)
+
+// Trace logs a message at TRACE (logger.LevelTrace) to l, with attributes
+// given as for slog.Logger.Info.
+func Trace(l *slog.Logger, msg string, args ...any) {
+	logAt(context.Background(), l, logger.LevelTrace, msg, args...)
+}
+
+// TraceContext logs a message at TRACE to l with the given context, as for
+// slog.Logger.InfoContext.
+func TraceContext(ctx context.Context, l *slog.Logger, msg string, args ...any) {
+	logAt(ctx, l, logger.LevelTrace, msg, args...)
+}
+
+// Fatal logs a message at FATAL to l, with attributes given as for
+// slog.Logger.Info, then ends the program by calling logger.Exit(1), which
+// writes out any buffered output first.
+func Fatal(l *slog.Logger, msg string, args ...any) {
+	logAt(context.Background(), l, logger.LevelFatal, msg, args...)
+	logger.Exit(1)
+}
+
+// Panic logs a message at PANIC to l, with attributes given as for
+// slog.Logger.Info, then writes out any buffered output (see logger.Flush)
+// and panics with the message.
+func Panic(l *slog.Logger, msg string, args ...any) {
+	logAt(context.Background(), l, logger.LevelPanic, msg, args...)
+	logger.Flush()
+	panic(msg)
+}
+
+// Log a record as slog.Logger.Log does, but with the caller of the exported
+// helper calling this as its source.
+func logAt(ctx context.Context, l *slog.Logger, level slog.Level, msg string, args ...any) {
+	if !l.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	// Skip runtime.Callers, logAt, and the helper.
+	runtime.Callers(3, pcs[:])
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(args...)
+	_ = l.Handler().Handle(ctx, r)
+}
@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Render a value which isn't one of slog's own kinds. Structs, maps, slices,
+// and arrays (or pointers to them) are shown as compact JSON, which reads far
+// better than their %+v form; anything else, values which know how to show
+// themselves (fmt.Stringers), and values which can't be marshalled are shown
+// with %+v.
+func formatAnyValue(v interface{}) string {
+	switch v.(type) {
+	case nil, fmt.Stringer, []byte:
+		return fmt.Sprintf("%+v", v)
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		if b, err := json.Marshal(v); err == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprintf("%+v", v)
+}
@@ -0,0 +1,11 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/geomyidia/zylog/internal/apitest"
+)
+
+func TestAPI(t *testing.T) {
+	apitest.Check(t, ".", "testdata/api.txt")
+}
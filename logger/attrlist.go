@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The rendered attributes of a line, each in the key={value} form followed by
+// a comma. Ordinarily, the keys of attributes in groups are qualified with the
+// dotted group names. In visual mode, the attributes of each top-level group
+// are instead clustered together under the group's name, at the position of
+// the group's first attribute, e.g.
+//
+//	http{req.id={x}, status={200}}, db{query={y}},
+//
+// If values is non-nil, attributes are instead recorded in it, keyed by their
+// qualified keys.
+type attrList struct {
+	values map[string]string
+	visual bool
+	items  []*attrItem
+	// The top-level group clusters, in visual mode.
+	groups map[string]*attrItem
+}
+
+// Either a single attribute or, in visual mode, a group cluster.
+type attrItem struct {
+	group string
+	b     strings.Builder
+}
+
+func (l *attrList) add(groups []string, key, value string) {
+	if l.values != nil {
+		if len(groups) > 0 {
+			key = strings.Join(groups, ".") + "." + key
+		}
+		l.values[key] = value
+		return
+	}
+	if !l.visual || len(groups) == 0 {
+		if len(groups) > 0 {
+			key = strings.Join(groups, ".") + "." + key
+		}
+		item := &attrItem{}
+		fmt.Fprintf(&item.b, "%s={%s}", key, value)
+		l.items = append(l.items, item)
+		return
+	}
+	if len(groups) > 1 {
+		key = strings.Join(groups[1:], ".") + "." + key
+	}
+	if l.groups == nil {
+		l.groups = map[string]*attrItem{}
+	}
+	item, ok := l.groups[groups[0]]
+	if ok {
+		item.b.WriteString(", ")
+	} else {
+		item = &attrItem{group: groups[0]}
+		l.groups[groups[0]] = item
+		l.items = append(l.items, item)
+	}
+	fmt.Fprintf(&item.b, "%s={%s}", key, value)
+}
+
+func (l *attrList) String() string {
+	var b strings.Builder
+	for _, item := range l.items {
+		if item.group != "" {
+			fmt.Fprintf(&b, "%s{%s}, ", item.group, item.b.String())
+		} else {
+			b.WriteString(item.b.String())
+			b.WriteString(", ")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,249 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The most allocations per record allowed for a plain message rendered in
+// the zylog text format, uncoloured and without the caller; see
+// TestAllocsBudget. Raise it only when a feature justifies the cost.
+const plainAllocsBudget = 4
+
+// Whether the race detector, which allocates for itself, is on.
+var raceEnabled = false
+
+// A handler writing records to io.Discard, as configured by the given
+// function.
+func benchHandler(tb testing.TB, configure func(*ZyLogOptions)) *SLogHandler {
+	tb.Helper()
+	opts := Default()
+	opts.Colored = false
+	if configure != nil {
+		configure(opts)
+	}
+	h, err := NewSLogHandler(io.Discard, opts)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return h
+}
+
+func benchSlog(b *testing.B, l *slog.Logger, args ...any) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info("a plain message", args...)
+	}
+}
+
+var fiveAttrs = []any{"one", 1, "two", "two", "three", 3.0, "four", true, "five", int64(5)}
+
+func BenchmarkSlogPlain(b *testing.B) {
+	benchSlog(b, slog.New(benchHandler(b, nil)))
+}
+
+func BenchmarkSlogFiveAttrs(b *testing.B) {
+	benchSlog(b, slog.New(benchHandler(b, nil)), fiveAttrs...)
+}
+
+func BenchmarkSlogWith(b *testing.B) {
+	l := slog.New(benchHandler(b, nil)).With("one", 1).With("two", "two").With("three", 3.0)
+	benchSlog(b, l)
+}
+
+func BenchmarkSlogGroups(b *testing.B) {
+	l := slog.New(benchHandler(b, nil)).WithGroup("outer").With("one", 1).WithGroup("inner")
+	benchSlog(b, l, "two", "two")
+}
+
+func BenchmarkSlogCaller(b *testing.B) {
+	h := benchHandler(b, func(opts *ZyLogOptions) { opts.ReportCaller = true })
+	benchSlog(b, slog.New(h))
+}
+
+func BenchmarkSlogColour(b *testing.B) {
+	h := benchHandler(b, func(opts *ZyLogOptions) { opts.ForceColor = true })
+	benchSlog(b, slog.New(h), fiveAttrs...)
+}
+
+func BenchmarkSlogJSON(b *testing.B) {
+	h := benchHandler(b, nil)
+	h.json = true
+	benchSlog(b, slog.New(h), fiveAttrs...)
+}
+
+func BenchmarkSlogBuffered(b *testing.B) {
+	opts := Default()
+	opts.Colored = false
+	opts.BufferSize = 64 * 1024
+	h := benchHandler(b, func(o *ZyLogOptions) { *o = *opts })
+	bw := buffered(opts, io.Discard).(*bufferedWriter)
+	defer bw.Close()
+	h.writer = bw
+	benchSlog(b, slog.New(h), fiveAttrs...)
+}
+
+// A logrus logger writing to io.Discard with the zylog formatter.
+func benchLogrus(colour, caller bool) *log.Logger {
+	l := log.New()
+	l.Out = io.Discard
+	l.Formatter = &TextFormatter{DisableColors: !colour, Colours: DefaultColours()}
+	l.ReportCaller = caller
+	return l
+}
+
+func BenchmarkLogrusPlain(b *testing.B) {
+	l := benchLogrus(false, false)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("a plain message")
+	}
+}
+
+func BenchmarkLogrusFiveFields(b *testing.B) {
+	l := benchLogrus(false, false)
+	fields := log.Fields{"one": 1, "two": "two", "three": 3.0, "four": true, "five": int64(5)}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.WithFields(fields).Info("a plain message")
+	}
+}
+
+func BenchmarkLogrusCaller(b *testing.B) {
+	l := benchLogrus(false, true)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("a plain message")
+	}
+}
+
+func BenchmarkLogrusColour(b *testing.B) {
+	l := benchLogrus(true, false)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("a plain message")
+	}
+}
+
+func BenchmarkLogrusJSON(b *testing.B) {
+	l := benchLogrus(false, false)
+	l.Formatter = &log.JSONFormatter{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("a plain message")
+	}
+}
+
+func TestAllocsBudget(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations aren't counted reliably under the race detector")
+	}
+	l := slog.New(benchHandler(t, nil))
+	allocs := testing.AllocsPerRun(100, func() {
+		l.Info("a plain message")
+	})
+	if allocs > plainAllocsBudget {
+		t.Errorf("%.0f allocations per record for a plain message, over the budget of %d", allocs, plainAllocsBudget)
+	}
+}
+
+// Records below the level should cost no more than the Enabled check, which
+// compares against the level parsed when the handler was made.
+func BenchmarkSlogEnabled(b *testing.B) {
+	h := benchHandler(b, nil)
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if h.Enabled(ctx, slog.LevelDebug) {
+			b.Fatal("debug enabled at the info level")
+		}
+	}
+}
+
+func BenchmarkSlogDisabled(b *testing.B) {
+	l := slog.New(benchHandler(b, nil))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Debug("a plain message", fiveAttrs...)
+	}
+}
+
+// With PackageLevels set, every record at or above the lowest package level
+// needs its caller's package; the level found is cached by program counter.
+func BenchmarkSlogPackageLevels(b *testing.B) {
+	h := benchHandler(b, func(opts *ZyLogOptions) {
+		opts.PackageLevels = map[string]string{
+			"github.com/geomyidia/zylog/logger": "debug",
+			"github.com/some/dep":               "warn",
+		}
+	})
+	l := slog.New(h)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Debug("a plain message")
+	}
+}
+
+func BenchmarkSlogPackageLevelsUnlisted(b *testing.B) {
+	h := benchHandler(b, func(opts *ZyLogOptions) {
+		opts.PackageLevels = map[string]string{"github.com/some/dep": "debug"}
+	})
+	l := slog.New(h)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Debug("a plain message")
+	}
+}
+
+// The PID is found once, when the handler is made; the goroutine ID is parsed
+// from a stack trace for every record.
+func BenchmarkSlogPID(b *testing.B) {
+	h := benchHandler(b, func(opts *ZyLogOptions) { opts.IncludePID = true })
+	benchSlog(b, slog.New(h))
+}
+
+func BenchmarkSlogGoroutineID(b *testing.B) {
+	h := benchHandler(b, func(opts *ZyLogOptions) { opts.IncludeGoroutineID = true })
+	benchSlog(b, slog.New(h))
+}
+
+func BenchmarkLogrusGoroutineID(b *testing.B) {
+	l := benchLogrus(false, false)
+	l.AddHook(&goroutineHook{})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("a plain message")
+	}
+}
+
+func BenchmarkGoroutineID(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		goroutineID()
+	}
+}
+
+// ProfileLabels off should cost nothing beyond BenchmarkSlogFiveAttrs; on,
+// it adds two pprof.Do calls and the timing of each record.
+func BenchmarkSlogProfileLabels(b *testing.B) {
+	for _, on := range []bool{false, true} {
+		name := "off"
+		if on {
+			name = "on"
+		}
+		b.Run(name, func(b *testing.B) {
+			h := benchHandler(b, func(opts *ZyLogOptions) {
+				opts.ProfileLabels = on
+				opts.Writer = io.Discard
+			})
+			benchSlog(b, slog.New(h), fiveAttrs...)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// How often buffered output is written out, unless the FlushInterval option
+// says otherwise.
+const defaultFlushInterval = time.Second
+
+// A writer which collects what's written to it, writing it out to w once it
+// would hold more than size bytes, at least once an interval, and when
+// flushed. It is safe for concurrent use, and never splits a single Write.
+type bufferedWriter struct {
+	mu   sync.Mutex
+	w    io.Writer
+	buf  []byte
+	size int
+	stop chan struct{}
+	once sync.Once
+	// The number of writes (records) buffered or being written out, which
+	// can be read without waiting for a write to w to finish.
+	records atomic.Int64
+}
+
+// Wrap w in a bufferedWriter if the BufferSize option asks for one; the
+// writer is tracked, so that Shutdown flushes it (before closing the outputs
+// opened earlier) and Exit flushes it. Writers which take the level of each
+// line, such as syslog's, aren't buffered.
+func buffered(opts *ZyLogOptions, w io.Writer) io.Writer {
+	if opts.BufferSize <= 0 || w == nil {
+		return w
+	}
+	if takesLevels(w) {
+		// Buffered lines would lose their levels.
+		return w
+	}
+	interval := opts.FlushInterval
+	if interval <= 0 {
+		interval = defaultFlushInterval
+	}
+	bw := &bufferedWriter{
+		w:    w,
+		buf:  make([]byte, 0, opts.BufferSize),
+		size: opts.BufferSize,
+		stop: make(chan struct{}),
+	}
+	go bw.flushEvery(interval)
+	track(bw)
+	return bw
+}
+
+func (bw *bufferedWriter) flushEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			bw.Flush()
+		case <-bw.stop:
+			return
+		}
+	}
+}
+
+func (bw *bufferedWriter) Write(p []byte) (int, error) {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	if len(bw.buf)+len(p) > bw.size {
+		if err := bw.flush(); err != nil {
+			return 0, err
+		}
+	}
+	if len(p) >= bw.size {
+		bw.records.Add(1)
+		defer bw.records.Add(-1)
+		return bw.w.Write(p)
+	}
+	bw.buf = append(bw.buf, p...)
+	bw.records.Add(1)
+	return len(p), nil
+}
+
+// Flush writes out whatever is buffered.
+func (bw *bufferedWriter) Flush() error {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	return bw.flush()
+}
+
+func (bw *bufferedWriter) flush() error {
+	if len(bw.buf) == 0 {
+		return nil
+	}
+	_, err := bw.w.Write(bw.buf)
+	bw.buf = bw.buf[:0]
+	// Everything buffered was written while bw.mu was held.
+	bw.records.Store(0)
+	return err
+}
+
+// The number of records buffered, or being written out, and so not yet
+// delivered.
+func (bw *bufferedWriter) undelivered() int64 {
+	return bw.records.Load()
+}
+
+// Close stops the periodic flushing and writes out whatever is buffered; the
+// writer being wrapped is left open. Anything written afterwards goes out
+// once the buffer fills, or on Flush.
+func (bw *bufferedWriter) Close() error {
+	bw.once.Do(func() { close(bw.stop) })
+	return bw.Flush()
+}
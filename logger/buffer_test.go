@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	opts := Default()
+	opts.Output = "filesystem"
+	opts.File = path
+	opts.BufferSize = 4096
+	opts.FlushInterval = time.Hour
+	defer slog.SetDefault(slog.Default())
+	logger, err := SetupSlog(opts)
+	if err != nil {
+		t.Fatalf("SetupSlog: %v", err)
+	}
+	defer Shutdown(context.Background())
+	logger.Info("buffered")
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "buffered") {
+		t.Fatalf("written before Flush: %q", data)
+	}
+	if err := Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "buffered") {
+		t.Errorf("not written by Flush: %q", data)
+	}
+}
@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/geomyidia/zylog/internal/paint"
+	log "github.com/sirupsen/logrus"
+)
+
+// The forms of the caller shown when the ReportCaller option is set (see
+// ZyLogOptions.CallerFormat).
+const (
+	CallerFunc     = "func"
+	CallerFile     = "file"
+	CallerFileFunc = "file-func"
+)
+
+// Format the caller segment of a line, e.g. " [pkg.Func:42]", as given by the
+// CallerFormat and CallerFullPath options.
+func formatCaller(p paint.Painter, c *Colours, format string, fullPath bool, function, file string, line int) string {
+	if !fullPath {
+		file = filepath.Base(file)
+	}
+	l := c.Line.paint(p, strconv.Itoa(line))
+	switch format {
+	case CallerFile:
+		return fmt.Sprintf(" [%s:%s]", c.Function.paint(p, file), l)
+	case CallerFileFunc:
+		return fmt.Sprintf(" [%s %s:%s]", c.Function.paint(p, function), c.Function.paint(p, file), l)
+	}
+	return fmt.Sprintf(" [%s:%s]", c.Function.paint(p, function), l)
+}
+
+// Find the caller skip frames further out than the one with the return
+// address pc (as held by slog records) on the calling goroutine's stack. A
+// skip which goes past the outermost frame outside the Go runtime stops
+// there; pc is returned as it is if it isn't on the stack.
+func skipCaller(pc uintptr, skip int) uintptr {
+	pcs := stackPCs()
+	for i, p := range pcs {
+		if p == pc {
+			return skipFrom(pcs, i, skip)
+		}
+	}
+	return pc
+}
+
+// The return addresses of the calling goroutine's stack, innermost first.
+func stackPCs() []uintptr {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(3, pcs)
+	for n == len(pcs) {
+		pcs = make([]uintptr, 2*len(pcs))
+		n = runtime.Callers(3, pcs)
+	}
+	return pcs[:n]
+}
+
+// Move skip frames out from pcs[i], stopping at the outermost frame outside
+// the Go runtime.
+func skipFrom(pcs []uintptr, i, skip int) uintptr {
+	last := len(pcs) - 1
+	for last > i && isRuntimeFrame(pcs[last]) {
+		last--
+	}
+	if i+skip > last {
+		return pcs[last]
+	}
+	return pcs[i+skip]
+}
+
+func isRuntimeFrame(pc uintptr) bool {
+	f, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return strings.HasPrefix(f.Function, "runtime.")
+}
+
+// A logrus hook which moves the caller of each entry out by the CallerSkip
+// option's number of frames (none, when logrus is routed to slog, whose
+// handler does the skipping).
+type callerHook struct {
+	skip int
+}
+
+func (ch *callerHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (ch *callerHook) Fire(entry *log.Entry) error {
+	if entry.Caller == nil {
+		return nil
+	}
+	// Rather than trusting the caller logrus found, skip from the first
+	// frame outside logrus (and zylog's hooks).
+	pcs := stackPCs()
+	for i, pc := range pcs {
+		f, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		if !isInternalFrame(f.Function) {
+			f, _ = runtime.CallersFrames([]uintptr{skipFrom(pcs, i, ch.skip)}).Next()
+			entry.Caller = &f
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// CaptureHandler is an slog.Handler which keeps what's logged in memory, for
+// tests of code that logs: both the records and the lines they're rendered
+// as, in the same form as SLogHandler writes them. Handlers derived with
+// WithAttrs, WithGroup, and WithName share the same store.
+type CaptureHandler struct {
+	handler slog.Handler
+	store   *captureStore
+}
+
+type captureStore struct {
+	// Held while handling a record, so that records and lines stay paired.
+	handling sync.Mutex
+	mu       sync.Mutex
+	records  []slog.Record
+	lines    []string
+}
+
+// The writer of a CaptureHandler's SLogHandler, which gets a single Write for
+// each line.
+type captureWriter struct {
+	store *captureStore
+}
+
+func (cw *captureWriter) Write(p []byte) (int, error) {
+	cw.store.mu.Lock()
+	defer cw.store.mu.Unlock()
+	cw.store.lines = append(cw.store.lines, strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+// NewCaptureHandler creates a handler which renders records as SLogHandler
+// does with the given options (without colour, if they're nil), and keeps
+// them in memory. Output options, such as Output and Writer, are ignored.
+func NewCaptureHandler(opts *ZyLogOptions) (*CaptureHandler, error) {
+	if opts == nil {
+		opts = Default()
+		opts.Colored = false
+	}
+	store := &captureStore{}
+	h, err := NewSLogHandler(&captureWriter{store: store}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &CaptureHandler{handler: h, store: store}, nil
+}
+
+// Enabled reports whether the handler keeps records at the given level.
+func (c *CaptureHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return c.handler.Enabled(ctx, level)
+}
+
+// Handle renders the record and keeps both it and its line; a record which
+// isn't rendered (being filtered out by PackageLevels, say) isn't kept.
+func (c *CaptureHandler) Handle(ctx context.Context, r slog.Record) error {
+	c.store.handling.Lock()
+	defer c.store.handling.Unlock()
+	c.store.mu.Lock()
+	before := len(c.store.lines)
+	c.store.mu.Unlock()
+	err := c.handler.Handle(ctx, r)
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	if len(c.store.lines) > before {
+		c.store.records = append(c.store.records, r.Clone())
+	}
+	return err
+}
+
+func (c *CaptureHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &CaptureHandler{handler: c.handler.WithAttrs(attrs), store: c.store}
+}
+
+func (c *CaptureHandler) WithGroup(name string) slog.Handler {
+	return &CaptureHandler{handler: c.handler.WithGroup(name), store: c.store}
+}
+
+// WithName returns a handler whose records carry the given name (see Named).
+func (c *CaptureHandler) WithName(name string) slog.Handler {
+	return &CaptureHandler{handler: c.handler.(*SLogHandler).WithName(name), store: c.store}
+}
+
+// Records returns the records kept so far, in the order they were handled.
+func (c *CaptureHandler) Records() []slog.Record {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	return append([]slog.Record(nil), c.store.records...)
+}
+
+// Lines returns the lines kept so far, without their trailing newlines, one
+// for each record.
+func (c *CaptureHandler) Lines() []string {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	return append([]string(nil), c.store.lines...)
+}
+
+// Contains reports whether a record was kept at the given level whose line
+// contains substr, e.g. Contains(slog.LevelError, "timeout").
+func (c *CaptureHandler) Contains(level slog.Level, substr string) bool {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	for i, r := range c.store.records {
+		if r.Level == level && strings.Contains(c.store.lines[i], substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset discards the records and lines kept so far, e.g. between tests.
+func (c *CaptureHandler) Reset() {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	c.store.records = nil
+	c.store.lines = nil
+}
@@ -0,0 +1,469 @@
+package logger
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/geomyidia/zylog/internal/paint"
+)
+
+// Colour is how one part of a log line is coloured: a foreground and a
+// background colour, either of which may be left as color.Reset (the zero
+// value) to keep the terminal's own. Fg256 and Bg256, when set, give colours
+// of the 256-colour palette, and FgRGB and BgRGB 24-bit colours, which are
+// used instead of Fg and Bg (FgRGB and BgRGB taking precedence). On terminals
+// which don't advertise support for them (see paint.DetectDepth: COLORTERM
+// for 24-bit colour, TERM for 256 colours), the nearest colour they do
+// support is shown instead. Styles are text attributes shown as well, e.g.
+// color.Bold or color.Underline; color.Reset entries among them are ignored.
+//
+// As text (e.g. in a config file), a Colour is written as the names of its
+// colour and style constants, separated by spaces, e.g. "FgHiWhite BgRed
+// Bold"; "Reset" stands for no colour. 24-bit colours are written in hex,
+// and colours of the 256-colour palette by their number after "256:", with a
+// "bg:" prefix for the background, e.g. "#ff8800 bg:#202020" or
+// "256:208 bg:256:236".
+type Colour struct {
+	Fg     color.Attribute
+	Bg     color.Attribute
+	Fg256  *uint8
+	Bg256  *uint8
+	FgRGB  *RGB
+	BgRGB  *RGB
+	Styles []color.Attribute
+}
+
+// RGB is a 24-bit colour: its red, green, and blue components.
+type RGB [3]uint8
+
+// ParseRGB parses a 24-bit colour written in hex, e.g. "#ff8800".
+func ParseRGB(s string) (RGB, error) {
+	var c RGB
+	if len(s) != 7 || s[0] != '#' {
+		return c, fmt.Errorf("%w: %q (expected a hex colour such as #ff8800)", ErrUnknownColour, s)
+	}
+	b, err := hex.DecodeString(s[1:])
+	if err != nil {
+		return c, fmt.Errorf("%w: %q (expected a hex colour such as #ff8800)", ErrUnknownColour, s)
+	}
+	copy(c[:], b)
+	return c, nil
+}
+
+// String returns the colour in hex, e.g. "#ff8800".
+func (c RGB) String() string {
+	return "#" + hex.EncodeToString(c[:])
+}
+
+// Colours is the colour theme of the zylog text format.
+type Colours struct {
+	Time     Colour `json:"time" yaml:"time"`
+	Trace    Colour `json:"trace" yaml:"trace"`
+	Debug    Colour `json:"debug" yaml:"debug"`
+	Info     Colour `json:"info" yaml:"info"`
+	Warning  Colour `json:"warning" yaml:"warning"`
+	Error    Colour `json:"error" yaml:"error"`
+	Fatal    Colour `json:"fatal" yaml:"fatal"`
+	Panic    Colour `json:"panic" yaml:"panic"`
+	Name     Colour `json:"name" yaml:"name"`         // the name of a named logger
+	Function Colour `json:"function" yaml:"function"` // caller function
+	Line     Colour `json:"line" yaml:"line"`         // caller line number
+	Arrow    Colour `json:"arrow" yaml:"arrow"`       // the ▶ before the message
+}
+
+// DefaultColours returns the colours zylog uses unless told otherwise.
+func DefaultColours() *Colours {
+	return &Colours{
+		Time:     Colour{Fg: color.FgGreen},
+		Trace:    Colour{Fg: color.FgHiMagenta},
+		Debug:    Colour{Fg: color.FgHiCyan},
+		Info:     Colour{Fg: color.FgHiGreen},
+		Warning:  Colour{Fg: color.FgHiYellow},
+		Error:    Colour{Fg: color.FgRed},
+		Fatal:    Colour{Fg: color.FgHiRed},
+		Panic:    Colour{Fg: color.FgHiWhite},
+		Name:     Colour{Fg: color.FgBlue},
+		Function: Colour{Fg: color.FgHiYellow},
+		Line:     Colour{Fg: color.FgYellow},
+		Arrow:    Colour{Fg: color.FgCyan},
+	}
+}
+
+// Coloured wraps an attribute value so that the slog handler's zylog text
+// format shows it in the given colour (when output is coloured), e.g.
+//
+//	logger.Error("request failed", "status", logger.Coloured(500, logger.Colour{Fg: color.FgRed}))
+//
+// Elsewhere (in JSON, the slog-text format, or other handlers) the value is
+// logged as if it hadn't been wrapped.
+func Coloured(value interface{}, c Colour) ColouredValue {
+	return ColouredValue{Value: value, Colour: c}
+}
+
+// ColouredValue is an attribute value with a colour; see Coloured.
+type ColouredValue struct {
+	Value  interface{}
+	Colour Colour
+}
+
+// LogValue returns the wrapped value.
+func (v ColouredValue) LogValue() slog.Value {
+	return slog.AnyValue(v.Value)
+}
+
+// The colours given by the options: Colours, or else the preset named by
+// Theme, or else the defaults.
+func coloursFor(opts *ZyLogOptions) *Colours {
+	if opts.Colours != nil {
+		return opts.Colours
+	}
+	return themeColours(opts)
+}
+
+// The colours of the preset named by the Theme option, or the defaults when
+// no theme is given. An unknown theme (which Validate rejects) gives the
+// defaults.
+func themeColours(opts *ZyLogOptions) *Colours {
+	if opts.Theme != "" {
+		if c, err := PresetColours(opts.Theme); err == nil {
+			return c
+		}
+	}
+	return DefaultColours()
+}
+
+// ResolveColour reports whether output to w is coloured, as both backends
+// decide it. The options, where they say anything, win over the environment.
+// In order of precedence:
+//
+//   - syslog is never coloured;
+//   - the ForceColor option turns colour on;
+//   - the Colored option, when not set, turns it off;
+//   - the NO_COLOR environment variable, set to anything but "", turns it
+//     off;
+//   - FORCE_COLOR or CLICOLOR_FORCE, set to anything but "", "0", or
+//     "false", turn it on (see forceColour);
+//   - otherwise, colour is used unless w is a file which isn't a terminal
+//     (e.g. stdout redirected to a file or a pipe, or filesystem output);
+//     other writers are coloured.
+func ResolveColour(opts *ZyLogOptions, w io.Writer) bool {
+	if _, ok := w.(*syslogWriter); ok {
+		return false
+	}
+	if opts.ForceColor {
+		return true
+	}
+	if !opts.Colored {
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if forceColour() {
+		return true
+	}
+	switch w.(type) {
+	case *os.File:
+		return paint.IsTerminal(w)
+	case *rotatingFile, *jsonArrayFile:
+		// Log files are never terminals.
+		return false
+	}
+	return true
+}
+
+// Whether the FORCE_COLOR or CLICOLOR_FORCE environment variables ask for
+// colour, even on outputs which aren't terminals: they do when set to
+// anything but "", "0", or "false".
+func forceColour() bool {
+	for _, name := range []string{"FORCE_COLOR", "CLICOLOR_FORCE"} {
+		switch strings.ToLower(os.Getenv(name)) {
+		case "", "0", "false":
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// The colour of the given (upper-case) level name; unknown levels aren't
+// coloured.
+func (c *Colours) level(name string) Colour {
+	switch name {
+	case "TRACE":
+		return c.Trace
+	case "DEBUG":
+		return c.Debug
+	case "INFO":
+		return c.Info
+	case "WARNING":
+		return c.Warning
+	case "ERROR":
+		return c.Error
+	case "FATAL":
+		return c.Fatal
+	case "PANIC":
+		return c.Panic
+	}
+	if cl, ok := lookupCustomLevel(name); ok {
+		return cl.colour
+	}
+	return Colour{}
+}
+
+func (c Colour) paint(p paint.Painter, s string) string {
+	var cs []paint.Colour
+	switch {
+	case c.FgRGB != nil:
+		cs = append(cs, p.FgRGB(c.FgRGB[0], c.FgRGB[1], c.FgRGB[2])...)
+	case c.Fg256 != nil:
+		cs = append(cs, p.Fg256(*c.Fg256)...)
+	case c.Fg != color.Reset:
+		cs = append(cs, c.Fg)
+	}
+	switch {
+	case c.BgRGB != nil:
+		cs = append(cs, p.BgRGB(c.BgRGB[0], c.BgRGB[1], c.BgRGB[2])...)
+	case c.Bg256 != nil:
+		cs = append(cs, p.Bg256(*c.Bg256)...)
+	case c.Bg != color.Reset:
+		cs = append(cs, c.Bg)
+	}
+	for _, a := range c.Styles {
+		if a != color.Reset {
+			cs = append(cs, a)
+		}
+	}
+	return p.Paint(s, cs...)
+}
+
+// The names of the fatih/color colour and style constants.
+var colourNames = map[string]color.Attribute{
+	"Reset": color.Reset,
+
+	"Bold":         color.Bold,
+	"Faint":        color.Faint,
+	"Italic":       color.Italic,
+	"Underline":    color.Underline,
+	"BlinkSlow":    color.BlinkSlow,
+	"BlinkRapid":   color.BlinkRapid,
+	"ReverseVideo": color.ReverseVideo,
+	"Concealed":    color.Concealed,
+	"CrossedOut":   color.CrossedOut,
+
+	"FgBlack":   color.FgBlack,
+	"FgRed":     color.FgRed,
+	"FgGreen":   color.FgGreen,
+	"FgYellow":  color.FgYellow,
+	"FgBlue":    color.FgBlue,
+	"FgMagenta": color.FgMagenta,
+	"FgCyan":    color.FgCyan,
+	"FgWhite":   color.FgWhite,
+
+	"FgHiBlack":   color.FgHiBlack,
+	"FgHiRed":     color.FgHiRed,
+	"FgHiGreen":   color.FgHiGreen,
+	"FgHiYellow":  color.FgHiYellow,
+	"FgHiBlue":    color.FgHiBlue,
+	"FgHiMagenta": color.FgHiMagenta,
+	"FgHiCyan":    color.FgHiCyan,
+	"FgHiWhite":   color.FgHiWhite,
+
+	"BgBlack":   color.BgBlack,
+	"BgRed":     color.BgRed,
+	"BgGreen":   color.BgGreen,
+	"BgYellow":  color.BgYellow,
+	"BgBlue":    color.BgBlue,
+	"BgMagenta": color.BgMagenta,
+	"BgCyan":    color.BgCyan,
+	"BgWhite":   color.BgWhite,
+
+	"BgHiBlack":   color.BgHiBlack,
+	"BgHiRed":     color.BgHiRed,
+	"BgHiGreen":   color.BgHiGreen,
+	"BgHiYellow":  color.BgHiYellow,
+	"BgHiBlue":    color.BgHiBlue,
+	"BgHiMagenta": color.BgHiMagenta,
+	"BgHiCyan":    color.BgHiCyan,
+	"BgHiWhite":   color.BgHiWhite,
+}
+
+// ParseColour returns the fatih/color colour (or style) constant with the
+// given name, e.g. "FgHiGreen", "BgBlue", "Bold", or "Reset"; the sixteen
+// ANSI colours may also be named plainly, e.g. "cyan" or "hi-cyan", with a
+// "bg:" prefix for backgrounds, e.g. "bg:hi-red". Case is ignored. An unknown
+// name results in ErrUnknownColour (wrapped).
+func ParseColour(name string) (color.Attribute, error) {
+	for n, c := range colourNames {
+		if strings.EqualFold(n, name) {
+			return c, nil
+		}
+	}
+	if c, ok := parseANSIName(name); ok {
+		return c, nil
+	}
+	return 0, fmt.Errorf("%w: %q (expected a name such as FgHiGreen, hi-cyan, bg:blue, or Reset)",
+		ErrUnknownColour, name)
+}
+
+// The plain names of the eight basic ANSI colours, in the order of their
+// codes.
+var ansiNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// Parse a plain colour name, e.g. "cyan", "hi-cyan", or "bg:hi-cyan".
+func parseANSIName(name string) (color.Attribute, bool) {
+	name = strings.ToLower(name)
+	base := color.FgBlack
+	if strings.HasPrefix(name, "bg:") {
+		base = color.BgBlack
+		name = name[len("bg:"):]
+	}
+	if strings.HasPrefix(name, "hi-") {
+		// The bright colours' codes are 60 on from the basic ones'.
+		base += color.FgHiBlack - color.FgBlack
+		name = name[len("hi-"):]
+	}
+	for i, n := range ansiNames {
+		if n == name {
+			return base + color.Attribute(i), true
+		}
+	}
+	return 0, false
+}
+
+// ParseColours returns the default colours (see DefaultColours) with the
+// parts named by the keys of m, e.g. "info" or "arrow" (as in a config file),
+// set to the colours given by their values, in the text form of Colour, e.g.
+// "hi-cyan bg:#202020 Bold". An unknown part or colour results in
+// ErrUnknownColour (wrapped), naming the key.
+func ParseColours(m map[string]string) (*Colours, error) {
+	c := DefaultColours()
+	parts := c.parts()
+	for key, value := range m {
+		part, ok := parts[strings.ToLower(key)]
+		if !ok {
+			return nil, fmt.Errorf("%w: no part of the line named %q", ErrUnknownColour, key)
+		}
+		if err := part.UnmarshalText([]byte(value)); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return c, nil
+}
+
+// The parts of the colours, by the names they have in config files.
+func (c *Colours) parts() map[string]*Colour {
+	return map[string]*Colour{
+		"time":     &c.Time,
+		"trace":    &c.Trace,
+		"debug":    &c.Debug,
+		"info":     &c.Info,
+		"warning":  &c.Warning,
+		"error":    &c.Error,
+		"fatal":    &c.Fatal,
+		"panic":    &c.Panic,
+		"name":     &c.Name,
+		"function": &c.Function,
+		"line":     &c.Line,
+		"arrow":    &c.Arrow,
+	}
+}
+
+func colourName(c color.Attribute) string {
+	for n, a := range colourNames {
+		if a == c {
+			return n
+		}
+	}
+	return fmt.Sprintf("%d", c)
+}
+
+// MarshalText encodes the colour as the names of its colour and style
+// constants.
+func (c Colour) MarshalText() ([]byte, error) {
+	var names []string
+	switch {
+	case c.FgRGB != nil:
+		names = append(names, c.FgRGB.String())
+	case c.Fg256 != nil:
+		names = append(names, "256:"+strconv.Itoa(int(*c.Fg256)))
+	case c.Fg != color.Reset:
+		names = append(names, colourName(c.Fg))
+	}
+	switch {
+	case c.BgRGB != nil:
+		names = append(names, "bg:"+c.BgRGB.String())
+	case c.Bg256 != nil:
+		names = append(names, "bg:256:"+strconv.Itoa(int(*c.Bg256)))
+	case c.Bg != color.Reset:
+		names = append(names, colourName(c.Bg))
+	}
+	for _, a := range c.Styles {
+		if a != color.Reset {
+			names = append(names, colourName(a))
+		}
+	}
+	if len(names) == 0 {
+		names = append(names, colourName(color.Reset))
+	}
+	return []byte(strings.Join(names, " ")), nil
+}
+
+// UnmarshalText decodes a colour from the names of its colour and style
+// constants (see ParseColour), hex colours (see ParseRGB), and 256-colour
+// palette numbers (e.g. "256:208"); Bg names and "bg:" names, hex colours,
+// and numbers give the background, style names the styles, and the others
+// the foreground.
+func (c *Colour) UnmarshalText(text []byte) error {
+	var colour Colour
+	for _, name := range strings.Fields(string(text)) {
+		if n, ok := strings.CutPrefix(strings.TrimPrefix(name, "bg:"), "256:"); ok {
+			i, err := strconv.ParseUint(n, 10, 8)
+			if err != nil {
+				return fmt.Errorf("%w: %q (expected a number from 0 to 255 after 256:)", ErrUnknownColour, name)
+			}
+			index := uint8(i)
+			if strings.HasPrefix(name, "bg:") {
+				colour.Bg256 = &index
+			} else {
+				colour.Fg256 = &index
+			}
+			continue
+		}
+		if strings.HasPrefix(strings.TrimPrefix(name, "bg:"), "#") {
+			rgb, err := ParseRGB(strings.TrimPrefix(name, "bg:"))
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(name, "bg:") {
+				colour.BgRGB = &rgb
+			} else {
+				colour.FgRGB = &rgb
+			}
+			continue
+		}
+		a, err := ParseColour(name)
+		if err != nil {
+			return err
+		}
+		switch {
+		case a == color.Reset:
+		case a >= color.Bold && a <= color.CrossedOut:
+			colour.Styles = append(colour.Styles, a)
+		case a >= color.BgBlack && a <= color.BgWhite,
+			a >= color.BgHiBlack && a <= color.BgHiWhite:
+			colour.Bg = a
+		default:
+			colour.Fg = a
+		}
+	}
+	*c = colour
+	return nil
+}
@@ -0,0 +1,266 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/geomyidia/zylog/internal/paint"
+)
+
+func TestResolveColour(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "log.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	tests := []struct {
+		name       string
+		colored    bool
+		force      bool
+		noColor    string
+		forceColor string
+		cliForce   string
+		w          io.Writer
+		want       bool
+	}{
+		{name: "colored", colored: true, w: &bytes.Buffer{}, want: true},
+		{name: "not colored", w: &bytes.Buffer{}, want: false},
+		{name: "file", colored: true, w: file, want: false},
+		{name: "ForceColor file", force: true, w: file, want: true},
+		{name: "ForceColor over NO_COLOR", force: true, noColor: "1", w: &bytes.Buffer{}, want: true},
+		{name: "NO_COLOR", colored: true, noColor: "1", w: &bytes.Buffer{}, want: false},
+		{name: "NO_COLOR over FORCE_COLOR", colored: true, noColor: "1", forceColor: "1", w: &bytes.Buffer{}, want: false},
+		{name: "FORCE_COLOR file", colored: true, forceColor: "1", w: file, want: true},
+		{name: "FORCE_COLOR=0 file", colored: true, forceColor: "0", w: file, want: false},
+		{name: "FORCE_COLOR=false file", colored: true, forceColor: "false", w: file, want: false},
+		{name: "CLICOLOR_FORCE file", colored: true, cliForce: "1", w: file, want: true},
+		{name: "Colored false over FORCE_COLOR", forceColor: "1", w: &bytes.Buffer{}, want: false},
+		{name: "Colored false over CLICOLOR_FORCE", cliForce: "1", w: file, want: false},
+		{name: "syslog", force: true, w: &syslogWriter{}, want: false},
+		{name: "log file", colored: true, w: &rotatingFile{}, want: false},
+		{name: "FORCE_COLOR log file", colored: true, forceColor: "1", w: &rotatingFile{}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			t.Setenv("FORCE_COLOR", tt.forceColor)
+			t.Setenv("CLICOLOR_FORCE", tt.cliForce)
+			opts := &ZyLogOptions{Colored: tt.colored, ForceColor: tt.force}
+			if got := ResolveColour(opts, tt.w); got != tt.want {
+				t.Errorf("ResolveColour = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Every element of a Colours, each given its own colour, and the escape code
+// it should be painted with.
+func distinctColours() (*Colours, map[string]string) {
+	c := &Colours{
+		Time:     Colour{Fg: color.FgGreen},
+		Trace:    Colour{Fg: color.FgMagenta},
+		Debug:    Colour{Fg: color.FgCyan},
+		Info:     Colour{Fg: color.FgHiGreen, Styles: []color.Attribute{color.Bold}},
+		Warning:  Colour{Fg: color.FgYellow, Bg: color.BgBlue},
+		Error:    Colour{Fg: color.FgRed},
+		Fatal:    Colour{Fg: color.FgHiRed, Styles: []color.Attribute{color.Reset, color.Underline}},
+		Panic:    Colour{Fg: color.FgHiWhite, Bg: color.BgRed},
+		Name:     Colour{Fg: color.FgBlue},
+		Function: Colour{Fg: color.FgHiYellow},
+		Line:     Colour{Fg: color.FgHiBlue},
+		Arrow:    Colour{Styles: []color.Attribute{color.Faint}},
+	}
+	return c, map[string]string{
+		"Time":     "\x1b[32m",
+		"Trace":    "\x1b[35m",
+		"Debug":    "\x1b[36m",
+		"Info":     "\x1b[92;1m",
+		"Warning":  "\x1b[33;44m",
+		"Error":    "\x1b[31m",
+		"Fatal":    "\x1b[91;4m",
+		"Panic":    "\x1b[97;41m",
+		"Name":     "\x1b[34m",
+		"Function": "\x1b[93m",
+		"Line":     "\x1b[94m",
+		"Arrow":    "\x1b[2m",
+	}
+}
+
+func TestColoursBytes(t *testing.T) {
+	clearColourEnv(t)
+	colours, codes := distinctColours()
+	var buf bytes.Buffer
+	opts := Default()
+	opts.ForceColor = true
+	opts.ReportCaller = true
+	opts.Level = "trace"
+	opts.TimestampFormat = "kitchen"
+	opts.Colours = colours
+	h, err := NewSLogHandler(&buf, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := Named(slog.New(h), "repl")
+	levels := []struct {
+		element, name string
+		level         slog.Level
+	}{
+		{"Trace", "TRACE", LevelTrace},
+		{"Debug", "DEBUG", slog.LevelDebug},
+		{"Info", "INFO", slog.LevelInfo},
+		{"Warning", "WARNING", slog.LevelWarn},
+		{"Error", "ERROR", slog.LevelError},
+		{"Fatal", "FATAL", LevelFatal},
+		{"Panic", "PANIC", LevelPanic},
+	}
+	for _, lv := range levels {
+		buf.Reset()
+		l.Log(context.Background(), lv.level, "message")
+		line := buf.String()
+		if !strings.HasPrefix(line, codes["Time"]) {
+			t.Errorf("%s: time not painted with %q: %q", lv.name, codes["Time"], line)
+		}
+		for _, want := range []string{
+			codes[lv.element] + lv.name + "\x1b[0m",
+			"[" + codes["Name"] + "repl" + "\x1b[0m]",
+			"[" + codes["Function"] + "github.com/geomyidia/zylog/logger.TestColoursBytes\x1b[0m:" + codes["Line"],
+			codes["Arrow"] + " ▶ \x1b[0mmessage\n",
+		} {
+			if !strings.Contains(line, want) {
+				t.Errorf("%s: %q not found in %q", lv.name, want, line)
+			}
+		}
+	}
+}
+
+func TestLogrusColoursBytes(t *testing.T) {
+	colours, codes := distinctColours()
+	tf := &TextFormatter{Colours: colours}
+	levels := map[log.Level]string{
+		log.TraceLevel: "Trace",
+		log.DebugLevel: "Debug",
+		log.InfoLevel:  "Info",
+		log.WarnLevel:  "Warning",
+		log.ErrorLevel: "Error",
+		log.FatalLevel: "Fatal",
+		log.PanicLevel: "Panic",
+	}
+	for level, element := range levels {
+		entry := log.NewEntry(log.New())
+		entry.Level = level
+		entry.Message = "message"
+		out, err := tf.Format(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		line := string(out)
+		name := strings.ToUpper(level.String())
+		if name == "WARN" {
+			name = "WARNING"
+		}
+		for _, want := range []string{
+			codes["Time"],
+			codes[element] + name + "\x1b[0m",
+			codes["Arrow"] + " ▶ \x1b[0mmessage",
+		} {
+			if !strings.Contains(line, want) {
+				t.Errorf("%s: %q not found in %q", name, want, line)
+			}
+		}
+	}
+}
+
+func TestColourDepths(t *testing.T) {
+	n208, n236 := uint8(208), uint8(236)
+	orange, grey := &RGB{255, 136, 0}, &RGB{32, 32, 32}
+	depths := []struct {
+		name, colorterm, term string
+	}{
+		{"truecolor", "truecolor", "xterm-256color"},
+		{"256", "", "xterm-256color"},
+		{"16", "", "xterm"},
+	}
+	tests := []struct {
+		name   string
+		colour Colour
+		want   map[string]string // by depth
+	}{
+		{"FgRGB", Colour{FgRGB: orange}, map[string]string{
+			"truecolor": "\x1b[38;2;255;136;0m",
+			"256":       "\x1b[38;5;208m",
+			"16":        "\x1b[33m",
+		}},
+		{"BgRGB", Colour{BgRGB: grey}, map[string]string{
+			"truecolor": "\x1b[48;2;32;32;32m",
+			"256":       "\x1b[48;5;234m",
+			"16":        "\x1b[40m",
+		}},
+		{"Fg256", Colour{Fg256: &n208}, map[string]string{
+			"truecolor": "\x1b[38;5;208m",
+			"256":       "\x1b[38;5;208m",
+			"16":        "\x1b[33m",
+		}},
+		{"Bg256", Colour{Bg256: &n236}, map[string]string{
+			"truecolor": "\x1b[48;5;236m",
+			"256":       "\x1b[48;5;236m",
+			"16":        "\x1b[40m",
+		}},
+		{"both with a style", Colour{FgRGB: orange, Bg256: &n236, Styles: []color.Attribute{color.Bold}},
+			map[string]string{
+				"truecolor": "\x1b[38;2;255;136;0;48;5;236;1m",
+				"256":       "\x1b[38;5;208;48;5;236;1m",
+				"16":        "\x1b[33;40;1m",
+			}},
+		{"FgRGB over Fg256 and Fg", Colour{Fg: color.FgRed, Fg256: &n236, FgRGB: orange}, map[string]string{
+			"truecolor": "\x1b[38;2;255;136;0m",
+			"256":       "\x1b[38;5;208m",
+			"16":        "\x1b[33m",
+		}},
+		{"Fg256 over Fg", Colour{Fg: color.FgRed, Fg256: &n208}, map[string]string{
+			"truecolor": "\x1b[38;5;208m",
+			"256":       "\x1b[38;5;208m",
+			"16":        "\x1b[33m",
+		}},
+		{"basic", Colour{Fg: color.FgRed, Bg: color.BgWhite}, map[string]string{
+			"truecolor": "\x1b[31;47m",
+			"256":       "\x1b[31;47m",
+			"16":        "\x1b[31;47m",
+		}},
+	}
+	for _, d := range depths {
+		t.Run(d.name, func(t *testing.T) {
+			t.Setenv("COLORTERM", d.colorterm)
+			t.Setenv("TERM", d.term)
+			p := paint.New(true)
+			for _, tt := range tests {
+				want := tt.want[d.name] + "text\x1b[0m"
+				if got := tt.colour.paint(p, "text"); got != want {
+					t.Errorf("%s: paint = %q, want %q", tt.name, got, want)
+				}
+			}
+			if got := (Colour{FgRGB: orange}).paint(paint.New(false), "text"); got != "text" {
+				t.Errorf("uncoloured paint = %q, want text", got)
+			}
+		})
+	}
+}
+
+func TestColourText(t *testing.T) {
+	var c Colour
+	if err := c.UnmarshalText([]byte("#ff8800 bg:256:236 Bold")); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("COLORTERM", "truecolor")
+	if got, want := c.paint(paint.New(true), "text"), "\x1b[38;2;255;136;0;48;5;236;1mtext\x1b[0m"; got != want {
+		t.Errorf("paint = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+)
+
+// A writer which writes a byte at a time, yielding between bytes, so that
+// concurrent Writes would interleave; it is safe for concurrent use only in
+// that its bytes aren't lost.
+type byteWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (bw *byteWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		bw.mu.Lock()
+		bw.buf.WriteByte(b)
+		bw.mu.Unlock()
+		runtime.Gosched()
+	}
+	return len(p), nil
+}
+
+func TestHandleConcurrent(t *testing.T) {
+	const goroutines, records = 300, 20
+	var w byteWriter
+	opts := Default()
+	opts.Colored = false
+	h, err := NewSLogHandler(&w, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Handlers derived from one another share the writer, and so its lock.
+	loggers := []*slog.Logger{
+		slog.New(h),
+		slog.New(h).With("with", "yes"),
+		slog.New(h).WithGroup("group"),
+		Named(slog.New(h), "named"),
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			l := loggers[g%len(loggers)]
+			for i := 0; i < records; i++ {
+				l.Info("concurrent record", "g", g, "i", i)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(w.buf.String(), "\n"), "\n")
+	if len(lines) != goroutines*records {
+		t.Fatalf("got %d lines, want %d", len(lines), goroutines*records)
+	}
+	seen := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		if strings.Count(line, "concurrent record") != 1 || strings.Count(line, " ▶ ") != 1 {
+			t.Fatalf("garbled line: %q", line)
+		}
+		i := strings.Index(line, "g={")
+		if i < 0 {
+			t.Fatalf("garbled line: %q", line)
+		}
+		var g, n int
+		if _, err := fmt.Sscanf(line[i:], "g={%d}, i={%d}", &g, &n); err != nil {
+			if _, err := fmt.Sscanf(line[i:], "g={%d}, group.i={%d}", &g, &n); err != nil {
+				t.Fatalf("garbled line: %q", line)
+			}
+		}
+		key := fmt.Sprint(g, "/", n)
+		if seen[key] {
+			t.Fatalf("record %s written twice", key)
+		}
+		seen[key] = true
+	}
+}
+
+func TestConcurrentSetup(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	defer Shutdown(context.Background())
+	clearColourEnv(t)
+	noColor := color.NoColor
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			opts := Default()
+			opts.Colored = i%2 == 0
+			opts.Writer = &syncBuffer{}
+			if i%4 < 2 {
+				opts.Logger = LogRUs
+				if _, err := SetupLoggingE(opts); err != nil {
+					t.Error(err)
+				}
+				log.Info("logrus")
+			} else {
+				l, err := SetupSlog(opts)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				l.Info("slog")
+			}
+		}(i)
+	}
+	wg.Wait()
+	if color.NoColor != noColor {
+		t.Errorf("color.NoColor changed from %v to %v", noColor, color.NoColor)
+	}
+}
+
+func TestIndependentColour(t *testing.T) {
+	clearColourEnv(t)
+	var coloured, plain syncBuffer
+	handler := func(w io.Writer, colour bool) *slog.Logger {
+		opts := Default()
+		opts.Colored = colour
+		h, err := NewSLogHandler(w, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return slog.New(h)
+	}
+	// Setting up a plain logger after the coloured one leaves it coloured.
+	cl := handler(&coloured, true)
+	pl := handler(&plain, false)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			cl.Info("coloured")
+		}()
+		go func() {
+			defer wg.Done()
+			pl.Info("plain")
+		}()
+	}
+	wg.Wait()
+	if n := strings.Count(coloured.String(), "\x1b[92mINFO\x1b[0m"); n != 50 {
+		t.Errorf("%d of 50 coloured records coloured", n)
+	}
+	if strings.Contains(plain.String(), "\x1b[") {
+		t.Errorf("plain records coloured: %q", plain.String())
+	}
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadFile reads options from a JSON or YAML file, as LoadReader does, taking
+// the format from the file's extension: .json, or .yaml or .yml.
+func LoadFile(path string) (*ZyLogOptions, error) {
+	var format string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		format = "json"
+	case ".yaml", ".yml":
+		format = "yaml"
+	default:
+		return nil, fmt.Errorf("unknown config file format: %s", path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	opts, err := LoadReader(f, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return opts, nil
+}
+
+// LoadReader reads options in the given format, json or yaml, over the
+// defaults. The keys are the snake_case forms of the option names (e.g.
+// report_caller), and the logger backend is given by name (slog or logrus);
+// unknown keys are rejected. Parts of the line not given colours keep those
+// of the theme (or the default ones).
+func LoadReader(r io.Reader, format string) (*ZyLogOptions, error) {
+	if format != "json" && format != "yaml" {
+		return nil, fmt.Errorf("unknown config format: %s", format)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	opts := Default()
+	if err := decodeConfig(data, format, opts); err != nil {
+		return nil, err
+	}
+	if opts.Colours != nil {
+		// The theme is only known once the whole file has been read, so the
+		// colours are read again over it.
+		opts.Colours = themeColours(opts)
+		if err := decodeConfig(data, format, opts); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := opts.ParsedLevel(); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// Decode options in the given format, json or yaml, over opts.
+func decodeConfig(data []byte, format string, opts *ZyLogOptions) error {
+	if format == "json" {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		return dec.Decode(opts)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(opts); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
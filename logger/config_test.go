@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Log a record at each of info and warn with options loaded from a config,
+// returning the output.
+func logWithConfig(t *testing.T, config, format string) string {
+	t.Helper()
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+	opts, err := LoadReader(strings.NewReader(config), format)
+	if err != nil {
+		t.Fatalf("LoadReader: %v", err)
+	}
+	if err := opts.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var buf bytes.Buffer
+	h, err := NewSLogHandler(&buf, opts)
+	if err != nil {
+		t.Fatalf("NewSLogHandler: %v", err)
+	}
+	l := slog.New(h)
+	l.Info("started")
+	l.Warn("running low")
+	return buf.String()
+}
+
+func TestLoadReaderTheme(t *testing.T) {
+	for _, format := range []string{"yaml", "json"} {
+		config := "theme: monochrome\n"
+		if format == "json" {
+			config = `{"theme": "monochrome"}`
+		}
+		out := logWithConfig(t, config, format)
+		if !strings.Contains(out, "\x1b[1mWARNING\x1b[0m") {
+			t.Errorf("%s: WARNING isn't bold: %q", format, out)
+		}
+		// The default theme's green time and hi-green INFO.
+		for _, code := range []string{"\x1b[32m", "\x1b[92m"} {
+			if strings.Contains(out, code) {
+				t.Errorf("%s: default colour %q used with the monochrome theme: %q", format, code, out)
+			}
+		}
+	}
+}
+
+func TestLoadReaderThemeColours(t *testing.T) {
+	out := logWithConfig(t, "theme: light\ncolours:\n  info: FgBlue\n", "yaml")
+	// Info as given, and the time as in the light theme.
+	for _, want := range []string{"\x1b[34mINFO\x1b[0m", "\x1b[90m"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q: %q", want, out)
+		}
+	}
+}
+
+func TestLoadReaderUnknownTheme(t *testing.T) {
+	opts, err := LoadReader(strings.NewReader("theme: nope\n"), "yaml")
+	if err != nil {
+		t.Fatalf("LoadReader: %v", err)
+	}
+	if err := opts.Validate(); err == nil {
+		t.Error("an unknown theme passed validation")
+	}
+}
+
+func TestLoadReaderTimestampRFC3339(t *testing.T) {
+	for _, format := range []string{"yaml", "json"} {
+		config := "timestamp_format: rfc3339\nutc: true\ncolored: false\n"
+		if format == "json" {
+			config = `{"timestamp_format": "rfc3339", "utc": true, "colored": false}`
+		}
+		out := logWithConfig(t, config, format)
+		// An RFC 3339 time in UTC, as with the standard format.
+		stamp := strings.Fields(out)[0]
+		if _, err := time.Parse(time.RFC3339, stamp); err != nil || !strings.HasSuffix(stamp, "Z") {
+			t.Errorf("%s: the timestamp isn't RFC 3339 in UTC: %q", format, out)
+		}
+	}
+}
+
+func TestLoadReaderLevel(t *testing.T) {
+	for _, format := range []string{"yaml", "json"} {
+		config := "level: WARNING\n"
+		if format == "json" {
+			config = `{"level": "WARNING"}`
+		}
+		opts, err := LoadReader(strings.NewReader(config), format)
+		if err != nil {
+			t.Fatalf("%s: LoadReader: %v", format, err)
+		}
+		if opts.Level != WarnLevel {
+			t.Errorf("%s: Level = %q, want %q", format, opts.Level, WarnLevel)
+		}
+
+		config = strings.Replace(config, "WARNING", "loud", 1)
+		if _, err := LoadReader(strings.NewReader(config), format); !errors.Is(err, ErrLogLevel) {
+			t.Errorf("%s: an unknown level gave %v, want ErrLogLevel", format, err)
+		}
+	}
+}
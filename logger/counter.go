@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"log/slog"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// LevelCounter counts the records logged, by the lower-case name of their
+// level, e.g. "error"; see the Counter option. zylog doesn't depend on any
+// metrics library, so a Prometheus counter needs a small adapter, e.g.
+//
+//	type levelCounter struct{ *prometheus.CounterVec }
+//
+//	func (c levelCounter) Inc(level string) { c.WithLabelValues(level).Inc() }
+//
+// for a CounterVec named log_messages_total with a "level" label.
+type LevelCounter interface {
+	Inc(level string)
+}
+
+// Count a record at the given level with the Counter option, if given.
+func countLevel(opts *ZyLogOptions, level slog.Level) {
+	if opts.Counter != nil {
+		opts.Counter.Inc(strings.ToLower(slogLevelToString(level)))
+	}
+}
+
+// A logrus hook which counts the entries logged, by level.
+type counterHook struct {
+	opts *ZyLogOptions
+}
+
+func (ch *counterHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (ch *counterHook) Fire(entry *log.Entry) error {
+	countLevel(ch.opts, logrusToSlogLevel(entry.Level))
+	return nil
+}
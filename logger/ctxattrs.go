@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ContextAttr names a value carried by the contexts records are logged with,
+// e.g. a user ID put there with context.WithValue, and the key of the
+// attribute (or logrus field) it is logged under; see the ContextAttrs
+// option.
+type ContextAttr struct {
+	Key    string
+	CtxKey any
+}
+
+// The attributes for the values ctx carries of those asked for by the
+// ContextAttrs option; values it doesn't carry are skipped.
+func contextAttrs(ctx context.Context, cas []ContextAttr) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	var attrs []slog.Attr
+	for _, ca := range cas {
+		if v := ctx.Value(ca.CtxKey); v != nil {
+			attrs = append(attrs, slog.Any(ca.Key, v))
+		}
+	}
+	return attrs
+}
+
+// A logrus hook which adds the values asked for by the ContextAttrs option
+// from the entry's context (see Entry.WithContext).
+type contextAttrsHook struct {
+	attrs []ContextAttr
+}
+
+func (ch *contextAttrsHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (ch *contextAttrsHook) Fire(entry *log.Entry) error {
+	attrs := contextAttrs(entry.Context, ch.attrs)
+	if len(attrs) == 0 {
+		return nil
+	}
+	// As for stackHook, the fields are copied rather than added to.
+	data := make(log.Fields, len(entry.Data)+len(attrs))
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	for _, a := range attrs {
+		data[a.Key] = a.Value.Any()
+	}
+	entry.Data = data
+	return nil
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// The levels added with RegisterLevel.
+var customLevels struct {
+	mu      sync.RWMutex
+	byName  map[string]customLevel
+	byLevel map[slog.Level]customLevel
+}
+
+type customLevel struct {
+	name   string
+	level  slog.Level
+	colour Colour
+}
+
+// RegisterLevel adds a level of the given name (e.g. "NOTICE") at the given
+// slog level (e.g. slog.LevelInfo+2), shown in the given colour. From then on
+// the name is accepted, in any case, wherever a level is, and records logged
+// at exactly that level are shown with it (in upper case). Registering a name
+// again replaces it; the built-in level names can't be registered, and result
+// in ErrLogLevel (wrapped).
+//
+// Since logrus has a fixed set of levels, a custom level given as the Level
+// option of the logrus backend stands for the nearest logrus level below it.
+func RegisterLevel(name string, level slog.Level, colour Colour) error {
+	name = strings.ToUpper(name)
+	if _, ok := lookupCustomLevel(name); !ok {
+		if _, err := parseSlogLevel(name); err == nil {
+			return fmt.Errorf("%w: %s is a built-in level", ErrLogLevel, name)
+		}
+	}
+	customLevels.mu.Lock()
+	defer customLevels.mu.Unlock()
+	if customLevels.byName == nil {
+		customLevels.byName = make(map[string]customLevel)
+		customLevels.byLevel = make(map[slog.Level]customLevel)
+	}
+	if old, ok := customLevels.byName[name]; ok {
+		delete(customLevels.byLevel, old.level)
+	}
+	cl := customLevel{name: name, level: level, colour: colour}
+	customLevels.byName[name] = cl
+	customLevels.byLevel[level] = cl
+	return nil
+}
+
+// Find a custom level by name, in any case.
+func lookupCustomLevel(name string) (customLevel, bool) {
+	customLevels.mu.RLock()
+	defer customLevels.mu.RUnlock()
+	cl, ok := customLevels.byName[strings.ToUpper(name)]
+	return cl, ok
+}
+
+// Find the custom level registered at exactly the given level.
+func customLevelAt(level slog.Level) (customLevel, bool) {
+	customLevels.mu.RLock()
+	defer customLevels.mu.RUnlock()
+	cl, ok := customLevels.byLevel[level]
+	return cl, ok
+}
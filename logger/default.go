@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// The type of the handler which slog uses until a default is set.
+const stockHandlerType = "*slog.defaultHandler"
+
+// Ensures the warning about replacing a foreign default is only logged once.
+var foreignDefaultWarning sync.Once
+
+// IsDefault reports whether the slog default logger uses a zylog handler.
+func IsDefault() bool {
+	switch slog.Default().Handler().(type) {
+	case *SLogHandler, *fanoutHandler:
+		return true
+	}
+	return false
+}
+
+// Return the type of the slog default handler if it was installed by
+// something other than zylog or slog itself, or an empty string otherwise.
+func foreignDefault() string {
+	if IsDefault() {
+		return ""
+	}
+	handlerType := fmt.Sprintf("%T", slog.Default().Handler())
+	if handlerType == stockHandlerType {
+		return ""
+	}
+	return handlerType
+}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// A handler installed as the slog default by someone other than zylog.
+type foreignHandler struct {
+	slog.Handler
+}
+
+func installForeign(t *testing.T) *slog.Logger {
+	t.Helper()
+	l := slog.New(&foreignHandler{slog.NewTextHandler(&bytes.Buffer{}, nil)})
+	slog.SetDefault(l)
+	return l
+}
+
+func TestForeignDefaultRefused(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	foreign := installForeign(t)
+	if IsDefault() {
+		t.Error("IsDefault() with a foreign default")
+	}
+	opts := Default()
+	opts.Writer = &bytes.Buffer{}
+	opts.RefuseToReplaceForeignDefault = true
+	_, err := SetupSlog(opts)
+	if !errors.Is(err, ErrForeignDefault) {
+		t.Fatalf("SetupSlog = %v, want ErrForeignDefault", err)
+	}
+	if !strings.Contains(err.Error(), "*logger.foreignHandler") {
+		t.Errorf("error %q doesn't name the handler", err)
+	}
+	if slog.Default() != foreign {
+		t.Error("the foreign default was replaced")
+	}
+}
+
+func TestForeignDefaultWarning(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	defer Shutdown(context.Background())
+	foreignDefaultWarning = sync.Once{}
+	installForeign(t)
+	var buf bytes.Buffer
+	opts := Default()
+	opts.Writer = &buf
+	if _, err := SetupSlog(opts); err != nil {
+		t.Fatalf("SetupSlog: %v", err)
+	}
+	if !IsDefault() {
+		t.Error("!IsDefault() after SetupSlog")
+	}
+	want := "Replaced a foreign slog default handler"
+	if out := buf.String(); !strings.Contains(out, want) || !strings.Contains(out, "*logger.foreignHandler") {
+		t.Errorf("no warning naming the handler in %q", out)
+	}
+
+	// The warning is only given once.
+	installForeign(t)
+	buf.Reset()
+	if _, err := SetupSlog(opts); err != nil {
+		t.Fatalf("SetupSlog: %v", err)
+	}
+	if out := buf.String(); strings.Contains(out, want) {
+		t.Errorf("warned again: %q", out)
+	}
+}
+
+func TestZylogDefaultReplaced(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	defer Shutdown(context.Background())
+	foreignDefaultWarning = sync.Once{}
+	opts := Default()
+	opts.Writer = &bytes.Buffer{}
+	opts.RefuseToReplaceForeignDefault = true
+	if _, err := SetupSlog(opts); err != nil {
+		t.Fatalf("SetupSlog: %v", err)
+	}
+	// Replacing zylog's own default is neither refused nor warned about.
+	var buf bytes.Buffer
+	opts.Writer = &buf
+	if _, err := SetupSlog(opts); err != nil {
+		t.Fatalf("SetupSlog replacing zylog's default: %v", err)
+	}
+	if strings.Contains(buf.String(), "foreign") {
+		t.Errorf("warned about zylog's own handler: %q", buf.String())
+	}
+}
@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"log/slog"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The keys of the attributes added by the IncludeHostPID (and IncludePID)
+// options.
+const (
+	HostKey = "host"
+	PIDKey  = "pid"
+)
+
+// The attributes added to every record by the DefaultAttrs, DefaultFields,
+// IncludeHostPID, and IncludePID options, in that order.
+func defaultAttrs(opts *ZyLogOptions) []slog.Attr {
+	attrs := append([]slog.Attr(nil), opts.DefaultAttrs...)
+	attrs = append(attrs, mapToAttrs(opts.DefaultFields)...)
+	if opts.IncludeHostPID {
+		if host, err := os.Hostname(); err == nil {
+			attrs = append(attrs, slog.String(HostKey, host))
+		}
+	}
+	if opts.IncludeHostPID || opts.IncludePID {
+		attrs = append(attrs, slog.Int(PIDKey, os.Getpid()))
+	}
+	return attrs
+}
+
+// A logrus hook which adds the default fields to every entry, other than
+// those the entry already has.
+type defaultsHook struct {
+	fields log.Fields
+}
+
+func (dh *defaultsHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (dh *defaultsHook) Fire(entry *log.Entry) error {
+	// As for stackHook, the fields are copied rather than added to.
+	data := make(log.Fields, len(entry.Data)+len(dh.fields))
+	for k, v := range dh.fields {
+		data[k] = v
+	}
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	entry.Data = data
+	return nil
+}
@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FromEnv returns the default options, overridden by those of the following
+// environment variables which are set:
+//
+//	ZYLOG_LEVEL   the minimum level logged, e.g. debug
+//	ZYLOG_OUTPUT  stdout, stderr, or filesystem
+//	ZYLOG_FILE    the log file, for filesystem output
+//	ZYLOG_COLOUR  whether output is coloured (true or false); ZYLOG_COLOR
+//	              is also accepted
+//	ZYLOG_FORMAT  the output format: zylog or slog-text
+//	ZYLOG_CALLER  whether caller information is logged (true or false)
+//	ZYLOG_LOGGER  the backend: slog or logrus
+//	ZYLOG_TIMESTAMP_FORMAT
+//	              the form of timestamps, e.g. rfc3339-milli (see
+//	              TimestampFormat); ZYLOG_TIMESTAMP is also accepted
+//	ZYLOG_PAD_LEVEL
+//	              the levels whose names are padded to align, separated by
+//	              commas, e.g. info,warn,error (see PadLevels)
+//
+// An invalid value results in an error naming the variable.
+func FromEnv() (*ZyLogOptions, error) {
+	opts := Default()
+	if v, ok := os.LookupEnv("ZYLOG_LEVEL"); ok {
+		if _, err := parseSlogLevel(v); err != nil {
+			return nil, envError("ZYLOG_LEVEL", v, err)
+		}
+		opts.Level = Level(v)
+	}
+	if v, ok := os.LookupEnv("ZYLOG_FILE"); ok {
+		opts.File = v
+	}
+	if v, ok := os.LookupEnv("ZYLOG_OUTPUT"); ok {
+		if err := validateOutput(v, opts); err != nil {
+			return nil, envError("ZYLOG_OUTPUT", v, err)
+		}
+		opts.Output = v
+	}
+	if err := envBool("ZYLOG_COLOR", &opts.Colored); err != nil {
+		return nil, err
+	}
+	if err := envBool("ZYLOG_COLOUR", &opts.Colored); err != nil {
+		return nil, err
+	}
+	if v, ok := os.LookupEnv("ZYLOG_FORMAT"); ok {
+		switch v {
+		case FormatZylog, FormatSlogText:
+			opts.Format = v
+		default:
+			return nil, envError("ZYLOG_FORMAT", v, ErrInvalidOption)
+		}
+	}
+	if err := envBool("ZYLOG_CALLER", &opts.ReportCaller); err != nil {
+		return nil, err
+	}
+	if v, ok := os.LookupEnv("ZYLOG_LOGGER"); ok {
+		backend, err := ParseBackend(v)
+		if err != nil {
+			return nil, envError("ZYLOG_LOGGER", v, err)
+		}
+		opts.Logger = backend
+	}
+	for _, name := range []string{"ZYLOG_TIMESTAMP", "ZYLOG_TIMESTAMP_FORMAT"} {
+		if v, ok := os.LookupEnv(name); ok {
+			if _, ok := timestampLayouts[v]; !ok {
+				return nil, envError(name, v, ErrInvalidOption)
+			}
+			opts.TimestampFormat = v
+		}
+	}
+	if v, ok := os.LookupEnv("ZYLOG_PAD_LEVEL"); ok {
+		var levels []string
+		for _, name := range strings.Split(v, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if _, err := parseSlogLevel(name); err != nil {
+				return nil, envError("ZYLOG_PAD_LEVEL", v, err)
+			}
+			levels = append(levels, name)
+		}
+		opts.PadLevels = levels
+	}
+	return opts, nil
+}
+
+// Set a boolean option from an environment variable, if it is set.
+func envBool(name string, opt *bool) error {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return envError(name, v, errors.New("not a boolean"))
+	}
+	*opt = b
+	return nil
+}
+
+func envError(name, value string, err error) error {
+	return fmt.Errorf("%s=%q: %w", name, value, err)
+}
@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var envVars = []string{
+	"ZYLOG_LEVEL", "ZYLOG_OUTPUT", "ZYLOG_FILE", "ZYLOG_COLOUR", "ZYLOG_COLOR",
+	"ZYLOG_FORMAT", "ZYLOG_CALLER", "ZYLOG_LOGGER", "ZYLOG_TIMESTAMP",
+	"ZYLOG_TIMESTAMP_FORMAT", "ZYLOG_PAD_LEVEL",
+}
+
+// Unset the variables read by FromEnv for the rest of the test, then set
+// those given.
+func setEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, name := range envVars {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+	for name, v := range env {
+		t.Setenv(name, v)
+	}
+}
+
+func TestFromEnvDefaults(t *testing.T) {
+	setEnv(t, nil)
+	opts, err := FromEnv()
+	if err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+	if !reflect.DeepEqual(opts, Default()) {
+		t.Errorf("FromEnv = %+v, want the defaults", opts)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	setEnv(t, map[string]string{
+		"ZYLOG_LEVEL":            "debug",
+		"ZYLOG_OUTPUT":           "stderr",
+		"ZYLOG_COLOUR":           "false",
+		"ZYLOG_FORMAT":           FormatSlogText,
+		"ZYLOG_CALLER":           "true",
+		"ZYLOG_LOGGER":           "logrus",
+		"ZYLOG_TIMESTAMP_FORMAT": TimestampRFC3339Milli,
+		"ZYLOG_PAD_LEVEL":        "info, warn,error",
+	})
+	opts, err := FromEnv()
+	if err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+	want := Default()
+	want.Level = "debug"
+	want.Output = "stderr"
+	want.Colored = false
+	want.Format = FormatSlogText
+	want.ReportCaller = true
+	want.Logger = LogRUs
+	want.TimestampFormat = TimestampRFC3339Milli
+	want.PadLevels = []string{"info", "warn", "error"}
+	if !reflect.DeepEqual(opts, want) {
+		t.Errorf("FromEnv = %+v, want %+v", opts, want)
+	}
+}
+
+func TestFromEnvColourSpellings(t *testing.T) {
+	// ZYLOG_COLOUR takes precedence over ZYLOG_COLOR.
+	setEnv(t, map[string]string{"ZYLOG_COLOR": "false", "ZYLOG_COLOUR": "true"})
+	opts, err := FromEnv()
+	if err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+	if !opts.Colored {
+		t.Error("ZYLOG_COLOUR=true didn't win over ZYLOG_COLOR=false")
+	}
+	setEnv(t, map[string]string{"ZYLOG_COLOR": "false"})
+	if opts, err = FromEnv(); err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+	if opts.Colored {
+		t.Error("ZYLOG_COLOR=false didn't turn colour off")
+	}
+}
+
+func TestFromEnvTimestampSpellings(t *testing.T) {
+	setEnv(t, map[string]string{"ZYLOG_TIMESTAMP": TimestampSimple})
+	opts, err := FromEnv()
+	if err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+	if opts.TimestampFormat != TimestampSimple {
+		t.Errorf("TimestampFormat = %q, want %s", opts.TimestampFormat, TimestampSimple)
+	}
+	// ZYLOG_TIMESTAMP_FORMAT takes precedence over ZYLOG_TIMESTAMP.
+	setEnv(t, map[string]string{"ZYLOG_TIMESTAMP": TimestampSimple, "ZYLOG_TIMESTAMP_FORMAT": TimestampRFC3339Nano})
+	if opts, err = FromEnv(); err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+	if opts.TimestampFormat != TimestampRFC3339Nano {
+		t.Errorf("TimestampFormat = %q, want %s", opts.TimestampFormat, TimestampRFC3339Nano)
+	}
+}
+
+func TestFromEnvTimestampRFC3339(t *testing.T) {
+	setEnv(t, map[string]string{"ZYLOG_TIMESTAMP_FORMAT": "rfc3339"})
+	opts, err := FromEnv()
+	if err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+	if opts.TimestampFormat != TimestampRFC3339 {
+		t.Errorf("TimestampFormat = %q, want %s", opts.TimestampFormat, TimestampRFC3339)
+	}
+}
+
+func TestFromEnvErrors(t *testing.T) {
+	tests := []struct {
+		name, value string
+		want        error
+	}{
+		{"ZYLOG_LEVEL", "loud", ErrLogLevel},
+		{"ZYLOG_OUTPUT", "printer", ErrUnsupLogOutput},
+		{"ZYLOG_COLOUR", "sometimes", nil},
+		{"ZYLOG_COLOR", "sometimes", nil},
+		{"ZYLOG_FORMAT", "xml", ErrInvalidOption},
+		{"ZYLOG_CALLER", "maybe", nil},
+		{"ZYLOG_LOGGER", "zap", ErrUnsupLogger},
+		{"ZYLOG_TIMESTAMP", "sundial", ErrInvalidOption},
+		{"ZYLOG_TIMESTAMP_FORMAT", "sundial", ErrInvalidOption},
+		{"ZYLOG_PAD_LEVEL", "info,loud", ErrLogLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, map[string]string{tt.name: tt.value})
+			_, err := FromEnv()
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if !strings.Contains(err.Error(), tt.name) {
+				t.Errorf("error %q doesn't name %s", err, tt.name)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package logger
+
+import "strconv"
+
+// The errors which err wraps, for the VerboseErrors option: the chain of
+// errors.Unwrap down to the root, or, on reaching an error which joins
+// several (as errors.Join does), its members.
+func errorCauses(err error) []error {
+	var causes []error
+	for {
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			return append(causes, e.Unwrap()...)
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+			if err == nil {
+				return causes
+			}
+			causes = append(causes, err)
+		default:
+			return causes
+		}
+	}
+}
+
+// The key under which the i'th cause of the error logged under key is shown,
+// e.g. error.cause.0.
+func causeKey(key string, i int) string {
+	return key + ".cause." + strconv.Itoa(i)
+}
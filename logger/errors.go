@@ -0,0 +1,20 @@
+package logger
+
+import "errors"
+
+// Errors returned by SetupLoggingE and the other setup functions. Their
+// messages match the values that SetupLogging has always panicked with, so
+// callers matching on the panic text keep working.
+var (
+	ErrLogLevel       = errors.New(LogLevelError)
+	ErrUnsupLogOutput = errors.New("Unsupported log output")
+	ErrNotImplemented = errors.New("Not yet implemented")
+	ErrUnsupLogger    = errors.New("Unsupported logger")
+	ErrForeignDefault = errors.New("Refusing to replace the slog default handler")
+	// Returned by zylog.New when an option is given more than once.
+	ErrConflictingOption = errors.New("Conflicting option")
+	ErrUnknownColour     = errors.New("Unknown colour")
+	// Returned for a value of an option other than the level, output, or
+	// backend which makes no sense, e.g. an unknown timestamp format.
+	ErrInvalidOption = errors.New("Invalid option")
+)
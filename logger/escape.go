@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Escape a rendered attribute value (or logrus field value) so that it can't
+// end its braces early or break the line: backslashes and closing braces are
+// escaped with a backslash, newlines, carriage returns, and tabs are written
+// as \n, \r, and \t, and other control characters as \xNN.
+func escapeValue(s string) string {
+	if !strings.ContainsAny(s, "\\}") && !hasControl(s) {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '}':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func hasControl(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] == 0x7f {
+			return true
+		}
+	}
+	return false
+}
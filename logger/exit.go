@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The function called by Exit, as given by the ExitFunc option of the last
+// setup.
+var exitFunc atomic.Pointer[func(int)]
+
+// Ensures logrus's ExitFunc is only set once, so that concurrent setups don't
+// race to write it.
+var logrusExitFunc sync.Once
+
+// Record the ExitFunc option for Exit, and have logrus's Fatal call Exit,
+// which writes out buffered output first.
+func setExitFunc(opts *ZyLogOptions) {
+	fn := opts.ExitFunc
+	if fn == nil {
+		fn = os.Exit
+	}
+	exitFunc.Store(&fn)
+	logrusExitFunc.Do(func() {
+		log.StandardLogger().ExitFunc = Exit
+	})
+}
+
+// Exit writes out anything the outputs opened by the setup functions are
+// holding on to, then ends the program with the given status code by calling
+// the ExitFunc option (os.Exit, unless it says otherwise).
+func Exit(code int) {
+	Flush()
+	if fn := exitFunc.Load(); fn != nil {
+		(*fn)(code)
+		return
+	}
+	os.Exit(code)
+}
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func TestLogrusFatalFlushes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	opts := Default()
+	opts.Logger = LogRUs
+	opts.Output = "filesystem"
+	opts.File = path
+	opts.BufferSize = 4096
+	opts.FlushInterval = time.Hour
+	var atExit string
+	opts.ExitFunc = func(int) {
+		data, _ := os.ReadFile(path)
+		atExit = string(data)
+	}
+	if err := SetupLogRUs(opts); err != nil {
+		t.Fatalf("SetupLogRUs: %v", err)
+	}
+	defer Shutdown(context.Background())
+	log.Info("before")
+	log.Fatal("fatal")
+	for _, msg := range []string{"before", "fatal"} {
+		if !strings.Contains(atExit, msg) {
+			t.Errorf("%q not written before exiting: %q", msg, atExit)
+		}
+	}
+}
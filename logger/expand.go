@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// How deeply expandValue descends, which also guards against cycles.
+const maxExpandDepth = 8
+
+// Expand a struct, map, slice, or array (or a pointer to one) into a group,
+// so that its fields, entries, or elements are rendered as nested keys: struct
+// fields by name (unexported ones are left out), map entries by key, and
+// elements by index. Values which are empty, or which know how to show
+// themselves (errors and fmt.Stringers), are left as they are.
+func expandValue(v interface{}, depth int) slog.Value {
+	if depth >= maxExpandDepth {
+		// Not left as an Any value, which would be expanded all over again.
+		return slog.StringValue(fmt.Sprintf("%+v", v))
+	}
+	switch v.(type) {
+	case nil, error, fmt.Stringer, Quantity, []byte:
+		return slog.AnyValue(v)
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return slog.AnyValue(v)
+		}
+		rv = rv.Elem()
+	}
+	var attrs []slog.Attr
+	switch rv.Kind() {
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < rv.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			attrs = append(attrs, expandAttr(t.Field(i).Name, rv.Field(i), depth))
+		}
+	case reflect.Map:
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		for _, k := range keys {
+			attrs = append(attrs, expandAttr(fmt.Sprint(k), rv.MapIndex(k), depth))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			attrs = append(attrs, expandAttr(strconv.Itoa(i), rv.Index(i), depth))
+		}
+	}
+	if len(attrs) == 0 {
+		return slog.AnyValue(v)
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func expandAttr(key string, rv reflect.Value, depth int) slog.Attr {
+	v := slog.AnyValue(rv.Interface())
+	if v.Kind() == slog.KindAny {
+		v = expandValue(rv.Interface(), depth+1)
+	}
+	return slog.Attr{Key: key, Value: v}
+}
@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type inner struct {
+	Tags []string
+	Meta map[string]int
+}
+
+type outer struct {
+	Name   string
+	Inner  inner
+	Ptr    *inner
+	hidden int
+}
+
+func nested() outer {
+	return outer{
+		Name:   "n",
+		Inner:  inner{Tags: []string{"a", "b"}, Meta: map[string]int{"y": 2, "x": 1}},
+		Ptr:    &inner{Tags: []string{"c"}},
+		hidden: 1,
+	}
+}
+
+// Log the nested struct as an Any attribute.
+func logNested(t *testing.T, expand, asJSON bool) string {
+	t.Helper()
+	var buf bytes.Buffer
+	opts := Default()
+	opts.Colored = false
+	opts.ExpandStructs = expand
+	h, err := NewSLogHandler(&buf, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.json = asJSON
+	slog.New(h).Info("m", slog.Any("obj", nested()))
+	return buf.String()
+}
+
+func TestExpandStructs(t *testing.T) {
+	out := logNested(t, true, false)
+	want := "|| obj.Name={n}, obj.Inner.Tags.0={a}, obj.Inner.Tags.1={b}, " +
+		"obj.Inner.Meta.x={1}, obj.Inner.Meta.y={2}, obj.Ptr.Tags.0={c}, obj.Ptr.Meta={null}, \n"
+	if !strings.HasSuffix(out, want) {
+		t.Errorf("got  %q\nwant it to end %q", out, want)
+	}
+}
+
+func TestAnyValueJSONText(t *testing.T) {
+	// Without ExpandStructs, the text format shows the value as JSON.
+	out := logNested(t, false, false)
+	want := `obj={{"Name":"n","Inner":{"Tags":["a","b"],"Meta":{"x":1,"y":2\}\},"Ptr":{"Tags":["c"],"Meta":null\}\}}, `
+	if !strings.Contains(out, want) {
+		t.Errorf("got  %q\nwant %q in it", out, want)
+	}
+}
+
+func TestAnyValueJSON(t *testing.T) {
+	for _, expand := range []bool{false, true} {
+		out := logNested(t, expand, true)
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(out), &record); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, out)
+		}
+		want := map[string]interface{}{
+			"Name": "n",
+			"Inner": map[string]interface{}{
+				"Tags": []interface{}{"a", "b"},
+				"Meta": map[string]interface{}{"x": 1.0, "y": 2.0},
+			},
+			"Ptr": map[string]interface{}{"Tags": []interface{}{"c"}, "Meta": nil},
+		}
+		if !reflect.DeepEqual(record["obj"], want) {
+			t.Errorf("expand=%v: obj = %#v, want %#v", expand, record["obj"], want)
+		}
+	}
+}
+
+// A struct which contains itself.
+type cycle struct {
+	Next *cycle
+}
+
+func TestExpandCycle(t *testing.T) {
+	c := &cycle{}
+	c.Next = c
+	v := expandValue(c, 0)
+	// The cycle is cut off at maxExpandDepth.
+	depth := 0
+	for v.Kind() == slog.KindGroup {
+		g := v.Group()
+		if len(g) != 1 || g[0].Key != "Next" {
+			t.Fatalf("unexpected group %v", g)
+		}
+		v = g[0].Value
+		depth++
+	}
+	if depth != maxExpandDepth || v.Kind() != slog.KindString {
+		t.Errorf("expanded to depth %d, ending in %v; want %d, ending in a string", depth, v.Kind(), maxExpandDepth)
+	}
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// Destination is one of several places to which the slog handler sends every
+// record (see ZyLogOptions.Destinations), each in its own form. The other
+// options, such as Level and the file rotation settings, are shared by all
+// destinations.
+type Destination struct {
+	Output     string `json:"output" yaml:"output"`           // stdout, stderr, or filesystem
+	File       string `json:"file" yaml:"file"`               // the log file, for filesystem output
+	FileFormat string `json:"file_format" yaml:"file_format"` // text, ndjson, or json-array
+	Colored    bool   `json:"colored" yaml:"colored"`
+}
+
+// The options for a single destination: those given, with the output, file,
+// and colouring of the destination. Outputs which the destinations share,
+// such as the summary writer, are left to the first destination.
+func destinationOptions(opts *ZyLogOptions, i int) *ZyLogOptions {
+	d := opts.Destinations[i]
+	o := *opts
+	o.Output = d.Output
+	o.File = d.File
+	o.FileFormat = d.FileFormat
+	o.Colored = d.Colored
+	o.Outputs = nil
+	o.Writer = nil
+	o.ErrorOutput = ""
+	o.Destinations = nil
+	if i > 0 {
+		o.SummaryWriter = nil
+		o.Counter = nil
+	}
+	return &o
+}
+
+// A handler which passes each record to several handlers.
+type fanoutHandler struct {
+	handlers []slog.Handler
+}
+
+// Create a handler for each of the destinations given by the options.
+func newFanoutHandler(opts *ZyLogOptions) (*fanoutHandler, error) {
+	f := &fanoutHandler{}
+	for i := range opts.Destinations {
+		h, err := newOutputHandler(destinationOptions(opts, i))
+		if err != nil {
+			return nil, err
+		}
+		f.handlers = append(f.handlers, h)
+	}
+	return f, nil
+}
+
+func (f *fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f.handlers {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle passes the record to each handler. A failure to handle it in one
+// doesn't prevent the others from handling it; all errors are returned
+// together.
+func (f *fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range f.handlers {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f *fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	f2 := &fanoutHandler{handlers: make([]slog.Handler, len(f.handlers))}
+	for i, h := range f.handlers {
+		f2.handlers[i] = h.WithAttrs(attrs)
+	}
+	return f2
+}
+
+func (f *fanoutHandler) WithGroup(name string) slog.Handler {
+	f2 := &fanoutHandler{handlers: make([]slog.Handler, len(f.handlers))}
+	for i, h := range f.handlers {
+		f2.handlers[i] = h.WithGroup(name)
+	}
+	return f2
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"log/slog"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// FieldsToAttrs converts logrus fields to slog attributes, sorted by key.
+// Values which are themselves fields (or map[string]interface{}) become
+// groups; other values are kept as they are.
+func FieldsToAttrs(fields log.Fields) []slog.Attr {
+	return mapToAttrs(fields)
+}
+
+func mapToAttrs(m map[string]interface{}) []slog.Attr {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		switch v := m[k].(type) {
+		case log.Fields:
+			attrs = append(attrs, slog.Attr{Key: k, Value: slog.GroupValue(mapToAttrs(v)...)})
+		case map[string]interface{}:
+			attrs = append(attrs, slog.Attr{Key: k, Value: slog.GroupValue(mapToAttrs(v)...)})
+		default:
+			attrs = append(attrs, slog.Any(k, v))
+		}
+	}
+	return attrs
+}
+
+// AttrsToFields converts slog attributes to logrus fields. Values are
+// resolved, and kept as their Go values (int64, string, time.Time, ...);
+// groups become nested fields, except for those with an empty key, whose
+// attributes are added to the enclosing fields, and empty groups, which are
+// left out.
+func AttrsToFields(attrs []slog.Attr) log.Fields {
+	fields := log.Fields{}
+	addFields(fields, attrs)
+	return fields
+}
+
+func addFields(fields log.Fields, attrs []slog.Attr) {
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		if a.Value.Kind() != slog.KindGroup {
+			if a.Key != "" {
+				fields[a.Key] = a.Value.Any()
+			}
+			continue
+		}
+		group := a.Value.Group()
+		switch {
+		case len(group) == 0:
+		case a.Key == "":
+			addFields(fields, group)
+		default:
+			nested := log.Fields{}
+			addFields(nested, group)
+			fields[a.Key] = nested
+		}
+	}
+}
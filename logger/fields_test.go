@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func TestFieldsToAttrs(t *testing.T) {
+	when := time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC)
+	got := FieldsToAttrs(log.Fields{
+		"z":    "last",
+		"n":    42,
+		"when": when,
+		"http": log.Fields{
+			"status": 200,
+			"req":    map[string]interface{}{"method": "GET", "path": "/"},
+		},
+		"empty": log.Fields{},
+		"a":     []int{1, 2},
+	})
+	want := []slog.Attr{
+		slog.Any("a", []int{1, 2}),
+		slog.Group("empty"),
+		slog.Group("http",
+			slog.Group("req", slog.String("method", "GET"), slog.String("path", "/")),
+			slog.Int("status", 200),
+		),
+		slog.Int("n", 42),
+		slog.Time("when", when),
+		slog.String("z", "last"),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d attributes, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		// []int isn't comparable, so Attr.Equal can't be used for it.
+		if want[i].Key == "a" {
+			if got[i].Key != "a" || !reflect.DeepEqual(got[i].Value.Any(), []int{1, 2}) {
+				t.Errorf("attribute %d = %v, want %v", i, got[i], want[i])
+			}
+			continue
+		}
+		if !got[i].Equal(want[i]) {
+			t.Errorf("attribute %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+// A LogValuer which is resolved by AttrsToFields.
+type secret string
+
+func (s secret) LogValue() slog.Value {
+	return slog.StringValue("***")
+}
+
+func TestAttrsToFields(t *testing.T) {
+	when := time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC)
+	got := AttrsToFields([]slog.Attr{
+		slog.String("s", "text"),
+		slog.Int("n", 42),
+		slog.Float64("f", 1.5),
+		slog.Bool("b", true),
+		slog.Duration("d", time.Second),
+		slog.Time("when", when),
+		slog.Any("password", secret("hunter2")),
+		slog.Group("http",
+			slog.Int("status", 200),
+			slog.Group("req", slog.String("method", "GET")),
+			slog.Group("none"),
+		),
+		slog.Group("", slog.String("inlined", "yes")),
+		slog.Group("empty"),
+		slog.String("", "no key"),
+	})
+	want := log.Fields{
+		"s":        "text",
+		"n":        int64(42),
+		"f":        1.5,
+		"b":        true,
+		"d":        time.Second,
+		"when":     when,
+		"password": "***",
+		"http": log.Fields{
+			"status": int64(200),
+			"req":    log.Fields{"method": "GET"},
+		},
+		"inlined": "yes",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %#v\nwant %#v", got, want)
+	}
+}
+
+func TestFieldsRoundTrip(t *testing.T) {
+	fields := log.Fields{
+		"s":    "text",
+		"n":    int64(42),
+		"http": log.Fields{"status": int64(200), "req": log.Fields{"method": "GET"}},
+	}
+	if got := AttrsToFields(FieldsToAttrs(fields)); !reflect.DeepEqual(got, fields) {
+		t.Errorf("got  %#v\nwant %#v", got, fields)
+	}
+}
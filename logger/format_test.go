@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The time of the records in the golden tests.
+var goldenTime = time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC)
+
+// A single attribute, as logrus fields come in no particular order.
+var messageGolden = []struct {
+	name, msg, want string
+}{
+	{"attrs without a message", "", "2024-03-01T12:30:45Z INFO || key={v}, \n"},
+	{"attrs and a message", "hello", "2024-03-01T12:30:45Z INFO ▶ hello || key={v}, \n"},
+}
+
+func TestSlogNoMessage(t *testing.T) {
+	for _, tt := range messageGolden {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			opts := Default()
+			opts.Colored = false
+			h, err := NewSLogHandler(&buf, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := slog.NewRecord(goldenTime, slog.LevelInfo, tt.msg, 0)
+			r.AddAttrs(slog.String("key", "v"))
+			if err := h.Handle(context.Background(), r); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got  %q\nwant %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestLogrusNoMessage(t *testing.T) {
+	for _, tt := range messageGolden {
+		t.Run(tt.name, func(t *testing.T) {
+			tf := &TextFormatter{DisableColors: true, Colours: DefaultColours()}
+			entry := log.NewEntry(log.New())
+			entry.Time, entry.Level, entry.Message = goldenTime, log.InfoLevel, tt.msg
+			entry.Data = log.Fields{"key": "v"}
+			out, err := tf.Format(entry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != tt.want {
+				t.Errorf("got  %q\nwant %q", out, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The key of the attribute (or logrus field) added by the IncludeGoroutineID
+// option.
+const GoroutineKey = "gid"
+
+// The key of the attribute (or logrus field) added by the IncludeSequence
+// option.
+const SequenceKey = "seq"
+
+// The ID of the calling goroutine, from the header of its stack trace
+// ("goroutine 42 [running]:"); 0 if it can't be found. This is slow enough
+// to be used only when asked for.
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, err := strconv.ParseUint(string(b), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// A logrus hook which adds the ID of the logging goroutine to every entry.
+type goroutineHook struct{}
+
+func (gh *goroutineHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (gh *goroutineHook) Fire(entry *log.Entry) error {
+	// As for stackHook, the fields are copied rather than added to.
+	data := make(log.Fields, len(entry.Data)+1)
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	data[GoroutineKey] = goroutineID()
+	entry.Data = data
+	return nil
+}
+
+// A logrus hook which numbers the entries, from 1.
+type sequenceHook struct {
+	seq atomic.Uint64
+}
+
+func (sh *sequenceHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (sh *sequenceHook) Fire(entry *log.Entry) error {
+	data := make(log.Fields, len(entry.Data)+1)
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	data[SequenceKey] = sh.seq.Add(1)
+	entry.Data = data
+	return nil
+}
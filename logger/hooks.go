@@ -0,0 +1,74 @@
+package logger
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+// Replace the hooks added to the logrus standard logger by an earlier setup
+// with those the given options ask for (a stackHook for stack traces, a
+// callerHook for CallerSkip, a defaultsHook for the default fields, a
+// goroutineHook for IncludeGoroutineID, a sequenceHook for IncludeSequence,
+// a contextAttrsHook for ContextAttrs, a spanHook for SpanContext, a
+// counterHook for Counter, and a redactHook for RedactKeys and
+// RedactPattern), followed by the LogrusHooks; nil options just remove them.
+// Hooks added by anyone else are kept.
+func setHooks(opts *ZyLogOptions) {
+	hooks := make(log.LevelHooks)
+	for level, hs := range log.StandardLogger().Hooks {
+		for _, h := range hs {
+			switch h.(type) {
+			case *stackHook, *callerHook, *defaultsHook, *goroutineHook,
+				*sequenceHook, *contextAttrsHook, *spanHook, *counterHook,
+				*redactHook, *passedHook:
+			default:
+				hooks[level] = append(hooks[level], h)
+			}
+		}
+	}
+	if opts != nil {
+		if min, ok := stackLevel(opts); ok {
+			sh := &stackHook{opts: opts}
+			for _, level := range log.AllLevels {
+				if logrusToSlogLevel(level) >= min {
+					sh.levels = append(sh.levels, level)
+				}
+			}
+			hooks.Add(sh)
+		}
+		if opts.ReportCaller && opts.CallerSkip > 0 {
+			hooks.Add(&callerHook{skip: opts.CallerSkip})
+		}
+		if defaults := defaultAttrs(opts); len(defaults) > 0 {
+			hooks.Add(&defaultsHook{fields: AttrsToFields(defaults)})
+		}
+		if opts.IncludeGoroutineID {
+			hooks.Add(&goroutineHook{})
+		}
+		if opts.IncludeSequence {
+			hooks.Add(&sequenceHook{})
+		}
+		if len(opts.ContextAttrs) > 0 {
+			hooks.Add(&contextAttrsHook{attrs: opts.ContextAttrs})
+		}
+		if opts.SpanContext != nil {
+			hooks.Add(&spanHook{ids: opts.SpanContext})
+		}
+		if opts.Counter != nil {
+			hooks.Add(&counterHook{opts: opts})
+		}
+		// After the hooks adding fields, so that those are redacted too.
+		if r := newRedactor(opts); r != nil {
+			hooks.Add(&redactHook{redactor: r})
+		}
+		for _, h := range opts.LogrusHooks {
+			hooks.Add(&passedHook{h})
+		}
+	}
+	log.StandardLogger().ReplaceHooks(hooks)
+}
+
+// One of the LogrusHooks, wrapped so that setHooks can tell it from hooks added
+// by anyone else, and replace it when set up again rather than add it twice.
+type passedHook struct {
+	log.Hook
+}
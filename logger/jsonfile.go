@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// File formats for filesystem output.
+const (
+	FileFormatText      = "text"
+	FileFormatNDJSON    = "ndjson"
+	FileFormatJSONArray = "json-array"
+)
+
+// Determine the format in which records are written, validating the
+// FileFormat option. Formats other than text only apply to filesystem
+// output.
+func fileFormat(opts *ZyLogOptions) (string, error) {
+	switch opts.FileFormat {
+	case "", FileFormatText:
+		return FileFormatText, nil
+	case FileFormatNDJSON, FileFormatJSONArray:
+		if opts.Writer != nil || len(opts.Outputs) > 0 || opts.Output != "filesystem" {
+			return FileFormatText, nil
+		}
+		return opts.FileFormat, nil
+	}
+	return "", fmt.Errorf("%w: file format %s", ErrUnsupLogOutput, opts.FileFormat)
+}
+
+// A file holding a single JSON array of records, which is kept valid after
+// every write: each record is written over the closing bracket, which is then
+// written again after it. If the process dies part way through a write, the
+// file can be fixed with RepairJSONArray.
+//
+// Files in this format are not rotated.
+type jsonArrayFile struct {
+	mu    sync.Mutex
+	file  *os.File
+	empty bool
+}
+
+const (
+	jsonArrayOpen  = "[\n"
+	jsonArrayClose = "\n]\n"
+)
+
+func openJSONArrayFile(path string) (*jsonArrayFile, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if info.Size() == 0 {
+		if _, err := f.WriteString(jsonArrayOpen + "]\n"); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	empty, err := isEmptyJSONArray(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &jsonArrayFile{file: f, empty: empty}, nil
+}
+
+// Write records, each given as a JSON object followed by a newline; several
+// may come at once (e.g. from a bufferedWriter).
+func (ja *jsonArrayFile) Write(p []byte) (int, error) {
+	ja.mu.Lock()
+	defer ja.mu.Unlock()
+	var buf bytes.Buffer
+	var offset int64
+	var whence int
+	if ja.empty {
+		offset, whence = int64(len(jsonArrayOpen)), io.SeekStart
+	} else {
+		offset, whence = -int64(len(jsonArrayClose)), io.SeekEnd
+	}
+	first := ja.empty
+	for _, record := range bytes.Split(p, []byte("\n")) {
+		if len(bytes.TrimSpace(record)) == 0 {
+			continue
+		}
+		if !first {
+			buf.WriteString(",\n")
+		}
+		first = false
+		buf.Write(record)
+	}
+	if first {
+		// Nothing but blank lines.
+		return len(p), nil
+	}
+	buf.WriteString(jsonArrayClose)
+	if _, err := ja.file.Seek(offset, whence); err != nil {
+		return 0, err
+	}
+	if _, err := ja.file.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	ja.empty = false
+	return len(p), nil
+}
+
+// Close closes the file.
+func (ja *jsonArrayFile) Close() error {
+	ja.mu.Lock()
+	defer ja.mu.Unlock()
+	return ja.file.Close()
+}
+
+// Report whether a JSON array file, as written by jsonArrayFile, has no
+// records.
+func isEmptyJSONArray(f *os.File) (bool, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+	return info.Size() <= int64(len(jsonArrayOpen)+len("]\n")), nil
+}
+
+// RepairJSONArray fixes a file written with the json-array file format that
+// was left invalid by the process dying part way through a write. All
+// complete records are kept; a partially written record is dropped.
+func RepairJSONArray(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return errors.New("not a JSON array file")
+	}
+	var records []json.RawMessage
+	for dec.More() {
+		var rec json.RawMessage
+		if err := dec.Decode(&rec); err != nil {
+			break
+		}
+		records = append(records, rec)
+	}
+	var buf bytes.Buffer
+	buf.WriteString(jsonArrayOpen)
+	for i, rec := range records {
+		if i > 0 {
+			buf.WriteString(",\n")
+		}
+		buf.Write(rec)
+	}
+	if len(records) == 0 {
+		buf.WriteString("]\n")
+	} else {
+		buf.WriteString(jsonArrayClose)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJSONArrayBuffered(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.json")
+	opts := Default()
+	opts.Output = "filesystem"
+	opts.File = path
+	opts.FileFormat = FileFormatJSONArray
+	opts.BufferSize = 4096
+	defer slog.SetDefault(slog.Default())
+	logger, err := SetupSlog(opts)
+	if err != nil {
+		t.Fatalf("SetupSlog: %v", err)
+	}
+	logger.Info("one")
+	logger.Info("two", "n", 2)
+	logger.Warn("three")
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []map[string]interface{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, data)
+	}
+	// "Logging initialized." and the three above.
+	if len(records) != 4 {
+		t.Fatalf("got %d records, want 4:\n%s", len(records), data)
+	}
+	if msg := records[3]["msg"]; msg != "three" {
+		t.Errorf("last message = %v, want three", msg)
+	}
+}
+
+func TestRepairJSONArray(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.json")
+	ja, err := openJSONArrayFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range []string{"one", "two", "three"} {
+		if _, err := ja.Write([]byte(`{"msg":"` + msg + `","n":[1,2]}` + "\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ja.Write([]byte(`{"msg":"four","nested":{"a":"]"}}` + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ja.Close(); err != nil {
+		t.Fatal(err)
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stop the last write after each of its bytes: the fourth record is
+	// written over the closing bracket, which only follows it once the record
+	// is complete.
+	start := len(before) - len(jsonArrayClose)
+	complete := len(after) - len(jsonArrayClose)
+	for cut := start; cut < len(after); cut++ {
+		if err := os.WriteFile(path, after[:cut], 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := RepairJSONArray(path); err != nil {
+			t.Fatalf("cut at %d: RepairJSONArray: %v", cut, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var records []map[string]interface{}
+		if err := json.Unmarshal(data, &records); err != nil {
+			t.Fatalf("cut at %d: invalid JSON: %v\n%s", cut, err, data)
+		}
+		want := []string{"one", "two", "three"}
+		if cut >= complete {
+			want = append(want, "four")
+		}
+		if len(records) != len(want) {
+			t.Fatalf("cut at %d: got %d records, want %d:\n%s", cut, len(records), len(want), data)
+		}
+		for i, msg := range want {
+			if records[i]["msg"] != msg {
+				t.Errorf("cut at %d: record %d = %v, want %s", cut, i, records[i]["msg"], msg)
+			}
+		}
+	}
+}
+
+func TestRepairJSONArrayEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.json")
+	if err := os.WriteFile(path, []byte(`[`+"\n"+`{"msg":"par`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := RepairJSONArray(path); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	var records []interface{}
+	if err := json.Unmarshal(data, &records); err != nil || len(records) != 0 {
+		t.Errorf("repaired %q: %v records, err %v", data, len(records), err)
+	}
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := RepairJSONArray(path); err == nil {
+		t.Error("repaired a file which isn't a JSON array")
+	}
+}
@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Level names a level, e.g. "debug", as given by the Level option.
+type Level string
+
+// The levels, by the names which ParseLevel gives.
+const (
+	TraceLevel Level = "trace"
+	DebugLevel Level = "debug"
+	InfoLevel  Level = "info"
+	WarnLevel  Level = "warn"
+	ErrorLevel Level = "error"
+	FatalLevel Level = "fatal"
+	PanicLevel Level = "panic"
+)
+
+// ParseLevel converts a level name to a Level, accepting any case, and
+// "warning" for WarnLevel. An unknown name results in ErrLogLevel (wrapped).
+func ParseLevel(name string) (Level, error) {
+	if _, err := parseSlogLevel(name); err != nil {
+		return "", fmt.Errorf("%w: %s", err, name)
+	}
+	name = strings.ToLower(name)
+	if name == "warning" {
+		return WarnLevel, nil
+	}
+	return Level(name), nil
+}
+
+// Slog converts the level to an slog level, e.g. LevelTrace for TraceLevel;
+// an unknown level gives slog.LevelInfo.
+func (l Level) Slog() slog.Level {
+	level, err := parseSlogLevel(string(l))
+	if err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// LogRUs converts the level to a logrus level; an unknown level gives
+// logrus.InfoLevel.
+func (l Level) LogRUs() log.Level {
+	level := l.Slog()
+	switch {
+	case level >= LevelPanic:
+		return log.PanicLevel
+	case level >= LevelFatal:
+		return log.FatalLevel
+	}
+	return slogToLogrusLevel(level)
+}
+
+// MarshalText encodes the level as its name.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l), nil
+}
+
+// UnmarshalText decodes a level name, as ParseLevel does.
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// ParsedLevel returns the Level option as ParseLevel gives it, e.g.
+// WarnLevel for "WARNING".
+func (opts *ZyLogOptions) ParsedLevel() (Level, error) {
+	return ParseLevel(string(opts.Level))
+}
+
+// The level of the loggers set up by SetupSlog and SetupLogRUs, which may be
+// changed with SetLevel while they're in use.
+var setupLevel slog.LevelVar
+
+// SetLevel changes the minimum level logged by the loggers set up by SetupSlog
+// and SetupLogRUs, taking effect immediately; it is safe to call while they're
+// in use. An unknown level results in ErrLogLevel.
+func SetLevel(level string) error {
+	l, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	setupLevel.Set(l.Slog())
+	log.SetLevel(l.LogRUs())
+	return nil
+}
+
+// GetLevel returns the minimum level logged by the loggers set up by
+// SetupSlog and SetupLogRUs, in lower case, e.g. "debug".
+func GetLevel() string {
+	return strings.ToLower(slogLevelToString(setupLevel.Level()))
+}
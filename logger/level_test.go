@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// A buffer safe for concurrent writes and reads.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (sb *syncBuffer) Write(p []byte) (int, error) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.Write(p)
+}
+
+func (sb *syncBuffer) String() string {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.String()
+}
+
+func (sb *syncBuffer) Reset() {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	sb.buf.Reset()
+}
+
+func TestSetLevelConcurrent(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	defer Shutdown(context.Background())
+	defer SetLevel("info")
+	var slogOut, logrusOut syncBuffer
+	opts := Default()
+	opts.Writer = &slogOut
+	l, err := SetupSlog(opts)
+	if err != nil {
+		t.Fatalf("SetupSlog: %v", err)
+	}
+	opts = Default()
+	opts.Logger = LogRUs
+	opts.Writer = &logrusOut
+	if err := SetupLogRUs(opts); err != nil {
+		t.Fatalf("SetupLogRUs: %v", err)
+	}
+
+	levels := []string{"trace", "debug", "info", "warn", "error"}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if err := SetLevel(levels[j%len(levels)]); err != nil {
+					t.Error(err)
+					return
+				}
+				GetLevel()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				l.Debug("slog debug", "j", j)
+				l.Info("slog info", "j", j)
+				log.WithField("j", j).Debug("logrus debug")
+				log.WithField("j", j).Info("logrus info")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := SetLevel("warn"); err != nil {
+		t.Fatal(err)
+	}
+	if got := GetLevel(); got != "warning" {
+		t.Errorf("GetLevel() = %q, want warning", got)
+	}
+	slogOut.Reset()
+	logrusOut.Reset()
+	l.Info("hidden")
+	l.Warn("shown")
+	log.Info("hidden")
+	log.Warn("shown")
+	for name, out := range map[string]string{"slog": slogOut.String(), "logrus": logrusOut.String()} {
+		if strings.Contains(out, "hidden") || !strings.Contains(out, "shown") {
+			t.Errorf("%s at warn: %q", name, out)
+		}
+	}
+	if err := SetLevel("loud"); err == nil {
+		t.Error("SetLevel accepted an unknown level")
+	}
+}
+
+func TestSetLevelLogrusFatal(t *testing.T) {
+	defer SetLevel("info")
+	tests := []struct {
+		level string
+		want  log.Level
+	}{
+		{"trace", log.TraceLevel},
+		{"debug", log.DebugLevel},
+		{"info", log.InfoLevel},
+		{"warning", log.WarnLevel},
+		{"error", log.ErrorLevel},
+		{"fatal", log.FatalLevel},
+		{"panic", log.PanicLevel},
+	}
+	for _, tt := range tests {
+		if err := SetLevel(tt.level); err != nil {
+			t.Fatal(err)
+		}
+		if got := log.GetLevel(); got != tt.want {
+			t.Errorf("SetLevel(%q): logrus level %v, want %v", tt.level, got, tt.want)
+		}
+	}
+
+	var buf syncBuffer
+	opts := Default()
+	opts.Logger = LogRUs
+	opts.Writer = &buf
+	if err := SetupLogRUs(opts); err != nil {
+		t.Fatalf("SetupLogRUs: %v", err)
+	}
+	defer Shutdown(context.Background())
+	if err := SetLevel("fatal"); err != nil {
+		t.Fatal(err)
+	}
+	log.Error("hidden")
+	if strings.Contains(buf.String(), "hidden") {
+		t.Errorf("error logged at the fatal level: %q", buf.String())
+	}
+}
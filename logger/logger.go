@@ -8,7 +8,7 @@ Zylog logger's primary features include:
 	* Exceedingly simple setup
 	* Colored output (enabled/disabled with a boolean)
 	* Logging level (lower-case string)
-	* Output (only stdout and stderr currently supported)
+	* Output (stdout, stderr, or a rotated file)
 	* ReportCaller (enabled/disabled with a boolean; prints package, function
 	  and line number)
 	* Custom format (similar to the Clojure twig library and the LFE logjam
@@ -17,6 +17,11 @@ Zylog logger's primary features include:
 Setup is done with the zylog logger, after which logrus may be used as designed
 by its author.
 
+The same formatting is available for the standard library's log/slog package
+via SLogHandler; SetupSlog creates one and installs it as the slog default.
+The slog handler additionally supports the ReplaceAttr option, which works as
+it does for the handlers in log/slog.
+
 Installation
 
 	$ go get github.com/geomyidia/zylog/logger
@@ -28,38 +33,45 @@ Additionally, there is a demo you may install and run:
 Configuration
 
 To configure the logger, simply pass an options struct reference to
-SetupLogging. For example,
+SetupLoggingE. For example,
 
-package main
+	package main
 
 	import (
 		logger "github.com/geomyidia/zylog/logger"
 		log "github.com/sirupsen/logrus"
 	)
 
-	func main () {
-		log.SetupLogging(&log.ZyLogOptions{
+	func main() {
+		_, err := logger.SetupLoggingE(&logger.ZyLogOptions{
 			Colored:      true,
 			Level:        "info",
 			Output:       "stdout",
 			ReportCaller: false,
 		})
+		if err != nil {
+			panic(err)
+		}
 		// More app code
 		log.Info("App started up!")
 	}
 
+SetupLogging is still available with its original signature, but it panics
+when the options can't be honoured; new code should prefer SetupLoggingE.
+
 */
 package logger
 
 import (
 	"bytes"
 	"fmt"
-	"os"
+	"io"
+	"log/slog"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/fatih/color"
+	"github.com/geomyidia/zylog/internal/paint"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -67,14 +79,326 @@ import (
 type TextFormatter struct {
 	// Force disabling colors.
 	DisableColors bool
+	// Pad the levels listed to the width of the widest of them.
+	PadLevels []string
+	// The colours used; nil for the defaults.
+	Colours *Colours
+	// Don't escape field values (see ZyLogOptions.NoEscape).
+	NoEscape bool
+	// How messages spanning several lines are shown (see
+	// ZyLogOptions.Multiline).
+	Multiline string
+	// The form of the caller (see ZyLogOptions.CallerFormat).
+	CallerFormat   string
+	CallerFullPath bool
+	// Show what error values wrap (see ZyLogOptions.VerboseErrors).
+	VerboseErrors bool
+	// The form of timestamps (see ZyLogOptions.TimestampFormat,
+	// CustomTimestampLayout, and ElapsedPrecision).
+	TimestampFormat       string
+	CustomTimestampLayout string
+	ElapsedPrecision      int
+	// The zone in which timestamps are shown; nil for the local one.
+	Location *time.Location
+}
+
+// Backend identifies the logging library which zylog.SetupLogging sets up.
+type Backend int
+
+// The supported backends.
+const (
+	Slog Backend = iota
+	LogRUs
+)
+
+// ParseBackend converts a backend name, slog or logrus, to a Backend.
+func ParseBackend(name string) (Backend, error) {
+	switch strings.ToLower(name) {
+	case "slog":
+		return Slog, nil
+	case "logrus":
+		return LogRUs, nil
+	}
+	return 0, fmt.Errorf("%w: %s", ErrUnsupLogger, name)
+}
+
+// String returns the name of the backend, as accepted by ParseBackend.
+func (b Backend) String() string {
+	switch b {
+	case Slog:
+		return "slog"
+	case LogRUs:
+		return "logrus"
+	}
+	return strconv.Itoa(int(b))
+}
+
+// MarshalText encodes the backend as its name.
+func (b Backend) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
 }
 
-// The Options used by the zylog logger to set up logrus.
+// UnmarshalText decodes a backend name, as ParseBackend does.
+func (b *Backend) UnmarshalText(text []byte) error {
+	backend, err := ParseBackend(string(text))
+	if err != nil {
+		return err
+	}
+	*b = backend
+	return nil
+}
+
+// The Options used by the zylog logger to set up logrus or slog.
 type ZyLogOptions struct {
-	Colored      bool
-	Level        string
-	Output       string // stdout, stderr, or filesystem
-	ReportCaller bool
+	// Logger is the backend used by zylog.SetupLogging; it defaults to slog.
+	Logger  Backend `json:"logger" yaml:"logger"`
+	Colored bool    `json:"colored" yaml:"colored"`
+	// ForceColor turns colour on whatever else says otherwise, even when
+	// writing to a file which isn't a terminal; by default, such output
+	// (e.g. stdout redirected to a file) is left uncoloured. See
+	// ResolveColour.
+	ForceColor bool `json:"force_color" yaml:"force_color"`
+	// Colours is the colour theme used when output is coloured; nil for
+	// the preset named by Theme, or DefaultColours.
+	Colours *Colours `json:"colours" yaml:"colours"`
+	// Theme names a colour preset (see ColourPresets), e.g. "light", used
+	// when Colours isn't given.
+	Theme string `json:"theme" yaml:"theme"`
+	// Level is the minimum level logged, e.g. DebugLevel; in a config file
+	// it is given by name (see ParseLevel for the names accepted).
+	Level  Level  `json:"level" yaml:"level"`
+	Output string `json:"output" yaml:"output"` // stdout, stderr, filesystem, or syslog
+	// File is the path of the log file used for filesystem output. The file
+	// is appended to, and rotated according to the following options (a
+	// zero value disables each).
+	File       string `json:"file" yaml:"file"`
+	MaxSizeMB  int    `json:"max_size_mb" yaml:"max_size_mb"`   // rotate when the file would exceed this size
+	MaxBackups int    `json:"max_backups" yaml:"max_backups"`   // number of rotated files to keep
+	MaxAgeDays int    `json:"max_age_days" yaml:"max_age_days"` // age after which rotated files are removed
+	Compress   bool   `json:"compress" yaml:"compress"`         // gzip rotated files
+	// FileFormat is the format of the log file: text (the default), ndjson
+	// (one JSON object per line), or json-array (a single JSON array, which
+	// is kept valid after every write but not rotated).
+	FileFormat string `json:"file_format" yaml:"file_format"`
+	// For syslog output, the daemon is reached over SyslogNetwork (e.g.
+	// "udp" or "tcp") at SyslogAddr, or locally when these are empty, and
+	// lines are tagged with SyslogTag (by default, the program name). Each
+	// line is sent uncoloured, at the severity matching its level: LOG_CRIT
+	// for fatal and panic, LOG_ERR, LOG_WARNING, LOG_INFO, and LOG_DEBUG
+	// for debug and trace.
+	SyslogNetwork string `json:"syslog_network" yaml:"syslog_network"`
+	SyslogAddr    string `json:"syslog_addr" yaml:"syslog_addr"`
+	SyslogTag     string `json:"syslog_tag" yaml:"syslog_tag"`
+	// Outputs, when non-empty, is used instead of Output to send each log
+	// line to several destinations. Colour codes are only written to the
+	// destinations that are terminals.
+	Outputs []string `json:"outputs" yaml:"outputs"`
+	// Destinations, when non-empty, is used by the slog handler instead of
+	// Output, Outputs, Writer, and ErrorOutput to send each record to
+	// several destinations, each with its own file format and colouring
+	// (e.g. coloured text on stdout and NDJSON in a file). It isn't
+	// supported for logrus, except with RouteLogRUs.
+	Destinations []Destination `json:"destinations" yaml:"destinations"`
+	// Writer, when non-nil, is where log lines are written; it takes
+	// precedence over Output and Outputs.
+	Writer io.Writer `json:"-" yaml:"-"`
+	// ErrorOutput, when set, is where records at ErrorLevel (by default
+	// warn) and above are sent instead of Output (or Outputs); it takes the
+	// same values as Output.
+	ErrorOutput  string `json:"error_output" yaml:"error_output"`
+	ErrorLevel   string `json:"error_level" yaml:"error_level"`
+	ReportCaller bool   `json:"report_caller" yaml:"report_caller"`
+	// CallerFormat is the form of the caller shown with ReportCaller: func
+	// (the default) for the function, e.g. [pkg.Func:42], file for the
+	// file, e.g. [file.go:42], or file-func for both, e.g. [pkg.Func
+	// file.go:42]. Files are shown by their base names, unless
+	// CallerFullPath is set.
+	CallerFormat   string `json:"caller_format" yaml:"caller_format"`
+	CallerFullPath bool   `json:"caller_full_path" yaml:"caller_full_path"`
+	// CallerSkip is the number of frames to move out from the call which
+	// logged a record when reporting its caller, so that a helper wrapping
+	// the logger isn't reported instead of its callers (as with zap's
+	// AddCallerSkip). A skip beyond the outermost frame gives that frame.
+	CallerSkip int `json:"caller_skip" yaml:"caller_skip"`
+	// PadLevels lists the levels (e.g. info, warn, error) whose names are
+	// padded to the width of the widest of them, so that lines at those
+	// levels align; other levels are never padded.
+	PadLevels []string `json:"pad_levels" yaml:"pad_levels"`
+	// InitLevel is the level of the "Logging initialized." message logged
+	// by the setup functions; the zero value is INFO. In config files it is
+	// given as an slog level name, e.g. WARN. For logrus, levels above
+	// ERROR are treated as ERROR.
+	InitLevel slog.Level `json:"init_level" yaml:"init_level"`
+	// RouteLogRUs makes logrus entries go through the slog handler (see
+	// SetupLogRUs), so that both backends share one output; it is meant for
+	// code part way through a move from logrus to slog.
+	RouteLogRUs bool `json:"route_logrus" yaml:"route_logrus"`
+	// PidFile, when set, is a path to which the process ID is written
+	// during setup.
+	PidFile string `json:"pid_file" yaml:"pid_file"`
+	// Format is the slog handler's output format: zylog (the default) or
+	// slog-text, which is the same as that of the log/slog TextHandler, but
+	// with zylog's timestamps, and is never coloured.
+	Format string `json:"format" yaml:"format"`
+	// GroupVisual makes the slog handler cluster the attributes of each
+	// top-level group under the group's name, e.g. http{method={GET}},
+	// rather than qualifying each key with the group name.
+	GroupVisual bool `json:"group_visual" yaml:"group_visual"`
+	// SummaryWriter, when set, is where the slog handler writes a compact,
+	// uncoloured summary line for each record at WARNING and above, e.g.
+	// "ERROR lost connection [request_id=x]", including only the attributes
+	// listed in SummaryKeys (qualified with their groups, e.g. "http.path").
+	SummaryWriter io.Writer `json:"-" yaml:"-"`
+	SummaryKeys   []string  `json:"summary_keys" yaml:"summary_keys"`
+	// RawValues makes the slog handler render attribute values with
+	// slog.Value.String, rather than according to their kind (times as the
+	// line timestamp, quantities scaled, and so on).
+	RawValues bool `json:"raw_values" yaml:"raw_values"`
+	// StackTraceLevel, when set, is the lowest level of the records (or
+	// logrus entries) which get a stack trace, as a "stack" attribute (or
+	// field) with one frame per line, starting at the caller of the logging
+	// function. StackDepth limits the number of frames (by default, 16).
+	// Frames in the Go runtime (runtime.main, runtime.goexit, ...) are left
+	// out unless StackRuntimeFrames is set.
+	StackTraceLevel    string `json:"stack_trace_level" yaml:"stack_trace_level"`
+	StackDepth         int    `json:"stack_depth" yaml:"stack_depth"`
+	StackRuntimeFrames bool   `json:"stack_runtime_frames" yaml:"stack_runtime_frames"`
+	// PackageLevels gives the minimum levels of particular packages, by
+	// their import paths, e.g. {"github.com/some/dep": "warn"}; a path
+	// applies to its subpackages too, the longest matching path being the
+	// one used. Named loggers (see Named) are matched by name instead, e.g.
+	// "repl" for repl.reader. Callers in packages not listed are subject to
+	// Level. These are only used by the slog handler, which has to find the
+	// caller of every record at the lowest of these levels, ReportCaller or
+	// not.
+	PackageLevels map[string]string `json:"package_levels" yaml:"package_levels"`
+	// TimestampFormat is the form of timestamps (and of time values in
+	// attributes and fields): standard (the default) or rfc3339 for RFC
+	// 3339, e.g. 2006-01-02T15:04:05Z07:00, simple for 20060102.150405,
+	// time-only for 15:04:05, simple-millis, simple-micros, and time-millis
+	// for those with milli- or microseconds, rfc3339-milli and rfc3339-nano
+	// for RFC 3339 with milli- or nanoseconds, unix and unix-millis for the
+	// seconds or milliseconds since the epoch, and elapsed for the time
+	// since logging was set up (see ResetEpoch), e.g. +0.482s, with
+	// ElapsedPrecision digits after the point (by default 3; at most 9).
+	// CustomTimestampLayout, when set, is used instead of TimestampFormat:
+	// it is a layout for time.Time.Format, e.g. "Jan _2 15:04:05".
+	TimestampFormat       string `json:"timestamp_format" yaml:"timestamp_format"`
+	CustomTimestampLayout string `json:"custom_timestamp_layout" yaml:"custom_timestamp_layout"`
+	ElapsedPrecision      int    `json:"elapsed_precision" yaml:"elapsed_precision"`
+	// Times are shown in the local zone, unless UTC is set, or another zone
+	// is given, by Location or by its IANA name as TimeLocation (e.g.
+	// "Europe/Berlin"), either of which takes precedence over UTC.
+	UTC          bool           `json:"utc" yaml:"utc"`
+	Location     *time.Location `json:"-" yaml:"-"`
+	TimeLocation string         `json:"time_location" yaml:"time_location"`
+	// Multiline is how the zylog text format shows messages which span
+	// several lines: raw (the default) writes them as they are, escape
+	// writes each newline as \n, and indent starts each further line with a
+	// "│" lined up under the "▶" before the message.
+	Multiline string `json:"multiline" yaml:"multiline"`
+	// ExpandStructs makes the slog handler's text format show struct, map,
+	// and slice attribute values as nested keys, e.g. user.Name={x},
+	// user.Tags.0={y}, rather than as compact JSON. (In JSON, they are
+	// always encoded as JSON.)
+	ExpandStructs bool `json:"expand_structs" yaml:"expand_structs"`
+	// VerboseErrors makes the zylog text format follow each error value
+	// with the errors it wraps, as error.cause.0={...}, error.cause.1={...},
+	// and so on (for an error logged under the key "error"). Error values
+	// are always shown in the Error colour.
+	VerboseErrors bool `json:"verbose_errors" yaml:"verbose_errors"`
+	// NoEscape turns off the escaping of attribute and field values in the
+	// zylog text format. By default, backslashes and closing braces are
+	// escaped with a backslash, and newlines and other control characters
+	// are written as escape sequences (\n, \x1b, ...), so that each record
+	// stays on one line and each value within its braces.
+	NoEscape bool `json:"no_escape" yaml:"no_escape"`
+	// SIUnits makes the slog handler scale quantities (see Quantity) using
+	// SI (1000) rather than binary (1024) prefixes.
+	SIUnits bool `json:"si_units" yaml:"si_units"`
+	// ProfileLabels makes the slog handler label its work for pprof and
+	// track its cost (see CPUCost); it is intended for diagnosing logging
+	// overhead.
+	ProfileLabels bool `json:"profile_labels" yaml:"profile_labels"`
+	// RefuseToReplaceForeignDefault makes SetupSlog return ErrForeignDefault
+	// rather than replace an slog default handler installed by something
+	// other than zylog or the slog package itself.
+	RefuseToReplaceForeignDefault bool `json:"refuse_to_replace_foreign_default" yaml:"refuse_to_replace_foreign_default"`
+	// BufferSize, when positive, has output collected in a buffer of that
+	// many bytes, and written out when full, every FlushInterval (by
+	// default, a second), and by Shutdown and Exit, trading a little latency
+	// for throughput. Records logged just before the program ends without
+	// calling Shutdown (or Exit) may be lost.
+	BufferSize    int           `json:"buffer_size" yaml:"buffer_size"`
+	FlushInterval time.Duration `json:"flush_interval" yaml:"flush_interval"`
+	// DefaultAttrs and DefaultFields are added to every record (or logrus
+	// entry), e.g. the app name and version; IncludeHostPID adds the host
+	// name and process ID too, as host and pid. They are shown after the
+	// record's own attributes, unless DefaultAttrsFirst is set. A record's
+	// own attribute with the same key is shown as well (or, for logrus and
+	// JSON, instead).
+	DefaultAttrs      []slog.Attr            `json:"-" yaml:"-"`
+	DefaultFields     map[string]interface{} `json:"default_fields" yaml:"default_fields"`
+	IncludeHostPID    bool                   `json:"include_host_pid" yaml:"include_host_pid"`
+	DefaultAttrsFirst bool                   `json:"default_attrs_first" yaml:"default_attrs_first"`
+	// IncludePID adds the process ID alone, as pid. IncludeGoroutineID adds
+	// the ID of the logging goroutine to each record, as gid; finding it is
+	// costly, so it is best kept for debugging.
+	IncludePID         bool `json:"include_pid" yaml:"include_pid"`
+	IncludeGoroutineID bool `json:"include_goroutine_id" yaml:"include_goroutine_id"`
+	// IncludeSequence numbers the records, from 1, as seq, so that lines
+	// lost or reordered when logs are merged can be found. Each handler
+	// (and the logrus setup) has its own count, which loggers derived with
+	// With and so on share.
+	IncludeSequence bool `json:"include_sequence" yaml:"include_sequence"`
+	// ContextAttrs names values, such as a user ID, which are logged with
+	// every record (or logrus entry, see Entry.WithContext) whose context
+	// carries them, so that request-scoped data need not be passed to each
+	// call; values a context doesn't carry are skipped. (Request IDs have
+	// their own support; see WithRequestID.)
+	ContextAttrs []ContextAttr `json:"-" yaml:"-"`
+	// SpanContext, when given, is called with the context of each record (or
+	// logrus entry, see Entry.WithContext) to find the active trace span,
+	// whose IDs are then added as trace_id and span_id, correlating logs
+	// with traces. See SpanContextFunc for use with OpenTelemetry.
+	SpanContext SpanContextFunc `json:"-" yaml:"-"`
+	// LogrusHooks are added to the logrus standard logger, e.g. to forward
+	// errors to an alerting system. They fire, in the order given, for
+	// every entry at their levels, before it's formatted (whatever the
+	// formatter) and after zylog's own hooks, so they see the fields those
+	// add (the default fields, gid, and so on), except when RouteLogRUs is
+	// set, when the slog handler adds them later. Setting up again replaces
+	// them; hooks added with logrus.AddHook are kept.
+	LogrusHooks []log.Hook `json:"-" yaml:"-"`
+	// Counter, when given, counts the records (or logrus entries) logged,
+	// by level, e.g. for a log_messages_total metric; see LevelCounter.
+	Counter LevelCounter `json:"-" yaml:"-"`
+	// Sampling, when given, limits how often records with the same level
+	// and message are logged, e.g. during an error storm; see Sampling.
+	// With logrus, hooks still fire for the entries it drops.
+	Sampling *Sampling `json:"sampling" yaml:"sampling"`
+	// RateLimits caps the number of records logged a second at each of
+	// the levels named, e.g. {"debug": 50}, with bursts of up to that many;
+	// records over the limit are dropped (though logrus hooks still fire
+	// for them). RateLimitSummary has the number dropped noted, in a line
+	// at the same level, before the next record allowed.
+	RateLimits       map[string]int `json:"rate_limits" yaml:"rate_limits"`
+	RateLimitSummary bool           `json:"rate_limit_summary" yaml:"rate_limit_summary"`
+	// RedactKeys and RedactPattern (a regular expression) name attributes
+	// and logrus fields whose values are replaced by *** before being
+	// formatted, e.g. "password" or "token|secret". Case is ignored. The key
+	// is matched, as are the names of the groups holding it and the dotted
+	// path to either, e.g. "auth.password". LogrusHooks see the fields
+	// redacted.
+	RedactKeys    []string `json:"redact_keys" yaml:"redact_keys"`
+	RedactPattern string   `json:"redact_pattern" yaml:"redact_pattern"`
+	// ReplaceAttr is only used by the slog handler; it has the same meaning
+	// as slog.HandlerOptions.ReplaceAttr.
+	ReplaceAttr func(groups []string, a slog.Attr) slog.Attr `json:"-" yaml:"-"`
+	// ExitFunc is called with the status code to end the program after a
+	// record is logged at FATAL (by logrus's Fatal, or the slog Fatal
+	// helper); it is os.Exit when not given, and may be replaced in tests.
+	ExitFunc func(code int) `json:"-" yaml:"-"`
 }
 
 const (
@@ -84,29 +408,115 @@ const (
 )
 
 // Logger setup function.
+//
+// Deprecated: SetupLogging panics when the options can't be honoured; use
+// SetupLoggingE instead, which returns an error.
 func SetupLogging(opts *ZyLogOptions) {
-	level, err := log.ParseLevel(opts.Level)
+	if _, err := SetupLoggingE(opts); err != nil {
+		panic(err.Error())
+	}
+}
+
+// SetupLoggingE configures logrus using the given options, as SetupLogRUs
+// does, returning the configured standard logger.
+func SetupLoggingE(opts *ZyLogOptions) (*log.Logger, error) {
+	if err := SetupLogRUs(opts); err != nil {
+		return nil, err
+	}
+	return log.StandardLogger(), nil
+}
+
+// SetupLogRUs configures the logrus standard logger using the given options.
+// Bad options result in one of ErrLogLevel, ErrUnsupLogOutput,
+// ErrInvalidOption, or ErrNotImplemented (possibly wrapped) and leave the
+// logrus configuration untouched.
+//
+// If the RouteLogRUs option is set, this is the same as SetupSlog: logrus
+// entries are converted to slog records and handled by the slog handler,
+// which is also installed as the slog default.
+//
+// Outputs opened by an earlier SetupLogRUs are closed once the new
+// configuration is in place.
+func SetupLogRUs(opts *ZyLogOptions) (err error) {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	if opts.RouteLogRUs {
+		if _, err := SetupSlog(opts); err != nil {
+			return err
+		}
+		// Logrus no longer writes to the outputs of its own setup.
+		endSetup(LogRUs, beginSetup(), nil)
+		return nil
+	}
+	mark := beginSetup()
+	defer func() { endSetup(LogRUs, mark, err) }()
+	if len(opts.Destinations) > 0 {
+		return fmt.Errorf("%w: destinations for logrus (see RouteLogRUs)", ErrNotImplemented)
+	}
+	level, err := opts.ParsedLevel()
 	if err != nil {
-		panic(LogLevelError)
-	}
-	log.SetLevel(level)
-	switch opts.Output {
-	case "stdout":
-		log.SetOutput(os.Stdout)
-	case "stderr":
-		log.SetOutput(os.Stderr)
-	case "filesystem":
-		panic(fmt.Sprintf(NotImplementedError, "filesystem log output"))
-	default:
-		panic(fmt.Sprintf(LogOutputError, opts.Output))
-	}
-	disableColors := !opts.Colored
-	color.NoColor = disableColors
-	log.SetFormatter(&TextFormatter{
-		DisableColors: disableColors,
-	})
+		return err
+	}
+	output, err := outputWriter(opts)
+	if err != nil {
+		return err
+	}
+	errOutput, err := errorOutputWriter(opts)
+	if err != nil {
+		return err
+	}
+	format, err := fileFormat(opts)
+	if err != nil {
+		return err
+	}
+	if err := writePidFile(opts); err != nil {
+		return err
+	}
+	text := &TextFormatter{
+		DisableColors:         !ResolveColour(opts, output),
+		PadLevels:             opts.PadLevels,
+		Colours:               coloursFor(opts),
+		NoEscape:              opts.NoEscape,
+		Multiline:             opts.Multiline,
+		CallerFormat:          opts.CallerFormat,
+		CallerFullPath:        opts.CallerFullPath,
+		VerboseErrors:         opts.VerboseErrors,
+		TimestampFormat:       opts.TimestampFormat,
+		CustomTimestampLayout: opts.CustomTimestampLayout,
+		Location:              timeLocation(opts),
+		ElapsedPrecision:      opts.ElapsedPrecision,
+	}
+	formatter := withErrorColours(opts, text, errOutput)
+	if format != FileFormatText {
+		formatter = &log.JSONFormatter{}
+	}
+	if s := newSampler(opts); s != nil {
+		formatter = &samplingFormatter{Formatter: formatter, sampler: s}
+	}
+	if rl := newRateLimiter(opts); rl != nil {
+		formatter = &limitingFormatter{Formatter: formatter, limiter: rl, summary: opts.RateLimitSummary}
+	}
+	output = buffered(opts, output)
+	errOutput = buffered(opts, errOutput)
+	if takesLevels(output) || errOutput != nil {
+		sw := &splitWriter{out: output, errOut: errOutput, errLevel: errorLevel(opts)}
+		output = sw
+		formatter = &splitFormatter{Formatter: formatter, writer: sw}
+	}
+	log.SetLevel(level.LogRUs())
+	setupLevel.Set(level.Slog())
+	ResetEpoch()
+	log.SetOutput(output)
+	log.SetFormatter(formatter)
 	log.SetReportCaller(opts.ReportCaller)
-	log.Info("Logging initialized.")
+	setHooks(opts)
+	setExitFunc(opts)
+	setRotationLogger(func(old, new, trigger string) {
+		log.WithFields(log.Fields{"old_file": old, "new_file": new, "trigger": trigger}).Info("Log file rotated.")
+	})
+	log.StandardLogger().Log(slogToLogrusLevel(opts.InitLevel), "Logging initialized.")
+	return nil
 }
 
 // Provides the custom formatting of the zylog logger.
@@ -131,49 +541,63 @@ func (f *TextFormatter) Format(entry *log.Entry) ([]byte, error) {
 		b = &bytes.Buffer{}
 	}
 
-	time := color.GreenString(entry.Time.Format(time.RFC3339))
-	level := ColorLevel(strings.ToUpper(entry.Level.String()))
+	p := paint.New(!f.DisableColors)
+	c := f.Colours
+	if c == nil {
+		c = DefaultColours()
+	}
+	tf := newTimeFormat(f.TimestampFormat, f.CustomTimestampLayout, f.Location, f.ElapsedPrecision)
+	ts := c.Time.paint(p, tf.pad(tf.format(entry.Time)))
+	name := strings.ToUpper(entry.Level.String())
+	level := colorLevel(p, c, name) + levelPadding(name, f.PadLevels)
 
-	b.WriteString(fmt.Sprintf("%s %s", time, level))
+	b.WriteString(fmt.Sprintf("%s %s", ts, level))
 	if entry.Logger.ReportCaller {
-		b.WriteString(fmt.Sprintf(" [%s:%s]",
-			color.HiYellowString(entry.Caller.Function),
-			color.YellowString(strconv.Itoa(entry.Caller.Line))))
+		b.WriteString(formatCaller(p, c, f.CallerFormat, f.CallerFullPath,
+			entry.Caller.Function, entry.Caller.File, entry.Caller.Line))
 	}
 	if entry.Message != "" {
-		b.WriteString(color.CyanString(" ▶ "))
-		b.WriteString(entry.Message)
+		start := b.String()
+		b.WriteString(c.Arrow.paint(p, " ▶ "))
+		b.WriteString(formatMessage(p, c, start, entry.Message, f.Multiline))
 	}
 
 	if len(entry.Data) > 0 {
 		b.WriteString(" || ")
 	}
 	for key, value := range entry.Data {
-		b.WriteString(fmt.Sprintf("%s={%s}, ", key, value))
+		err, isError := value.(error)
+		f.writeField(b, p, c, key, formatSlogValue(slog.AnyValue(value), tf), isError)
+		if isError && f.VerboseErrors {
+			for i, cause := range errorCauses(err) {
+				f.writeField(b, p, c, causeKey(key, i), cause.Error(), false)
+			}
+		}
 	}
 
 	b.WriteByte('\n')
 	return b.Bytes(), nil
 }
 
+// Write a field as key={value}, escaping the value unless told not to, and
+// colouring it if it's an error.
+func (f *TextFormatter) writeField(b *bytes.Buffer, p paint.Painter, c *Colours, key, value string, isError bool) {
+	if !f.NoEscape {
+		value = escapeValue(value)
+	}
+	if isError {
+		value = c.Error.paint(p, value)
+	}
+	b.WriteString(fmt.Sprintf("%s={%s}, ", key, value))
+}
+
 // Determine the color of the log level based upon the string value of the log
-// level.
+// level. The level is always coloured; formatters honour their own colour
+// settings instead of using this directly.
 func ColorLevel(level string) string {
-	switch level {
-	case "TRACE":
-		level = color.HiMagentaString(level)
-	case "DEBUG":
-		level = color.HiCyanString(level)
-	case "INFO":
-		level = color.HiGreenString(level)
-	case "WARNING":
-		level = color.HiYellowString(level)
-	case "ERROR":
-		level = color.RedString(level)
-	case "FATAL":
-		level = color.HiRedString(level)
-	case "PANIC":
-		level = color.HiWhiteString(level)
-	}
-	return level
+	return colorLevel(paint.New(true), DefaultColours(), level)
+}
+
+func colorLevel(p paint.Painter, c *Colours, level string) string {
+	return c.level(level).paint(p, level)
 }
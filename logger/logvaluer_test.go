@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// A LogValuer which resolves to a group.
+type user struct {
+	id   int
+	name string
+}
+
+func (u user) LogValue() slog.Value {
+	return slog.GroupValue(slog.Int("id", u.id), slog.String("name", u.name))
+}
+
+// A LogValuer which never resolves to anything else.
+type loop struct{}
+
+func (loop) LogValue() slog.Value {
+	return slog.AnyValue(loop{})
+}
+
+const loopMessage = "LogValue called too many times on Value of type logger.loop"
+
+// Log a record with LogValuers inside a group, in the given format.
+func logValuers(t *testing.T, format string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	opts := Default()
+	opts.Colored = false
+	if format == FormatSlogText {
+		opts.Format = format
+	}
+	h, err := NewSLogHandler(&buf, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.json = format == "json"
+	slog.New(h).WithGroup("req").Info("m", "user", user{7, "ann"}, "loop", loop{})
+	return buf.String()
+}
+
+func TestLogValuerGroupText(t *testing.T) {
+	out := logValuers(t, FormatZylog)
+	want := "|| req.user.id={7}, req.user.name={ann}, req.loop={" + loopMessage + "}, \n"
+	if !strings.HasSuffix(out, want) {
+		t.Errorf("got  %q\nwant it to end %q", out, want)
+	}
+}
+
+func TestLogValuerGroupSlogText(t *testing.T) {
+	out := logValuers(t, FormatSlogText)
+	want := ` msg=m req.user.id=7 req.user.name=ann req.loop="` + loopMessage + `"` + "\n"
+	if !strings.HasSuffix(out, want) {
+		t.Errorf("got  %q\nwant it to end %q", out, want)
+	}
+}
+
+func TestLogValuerGroupJSON(t *testing.T) {
+	out := logValuers(t, "json")
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &record); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	want := map[string]interface{}{
+		"user": map[string]interface{}{"id": 7.0, "name": "ann"},
+		"loop": loopMessage,
+	}
+	if !reflect.DeepEqual(record["req"], want) {
+		t.Errorf("req = %#v, want %#v", record["req"], want)
+	}
+}
@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// LogMemStats logs the memory allocator's statistics at DEBUG: the bytes
+// allocated and still in use (alloc), allocated in total (total_alloc), and
+// obtained from the system (sys), and the number of completed GC cycles
+// (num_gc). The byte counts are logged as Quantity values. A nil logger means
+// the slog default.
+//
+// Reading the statistics briefly stops the world, so nothing is read unless
+// DEBUG is enabled; even so, this is meant to be called occasionally, not on a
+// hot path.
+func LogMemStats(l *slog.Logger) {
+	if l == nil {
+		l = slog.Default()
+	}
+	ctx := context.Background()
+	if !l.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	// Report the caller of LogMemStats as the source, not LogMemStats itself.
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+	r := slog.NewRecord(time.Now(), slog.LevelDebug, "Memory stats", pcs[0])
+	r.AddAttrs(
+		slog.Any("alloc", Quantity{Value: float64(m.Alloc), Unit: "B"}),
+		slog.Any("total_alloc", Quantity{Value: float64(m.TotalAlloc), Unit: "B"}),
+		slog.Any("sys", Quantity{Value: float64(m.Sys), Unit: "B"}),
+		slog.Uint64("num_gc", uint64(m.NumGC)))
+	_ = l.Handler().Handle(ctx, r)
+}
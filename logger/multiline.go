@@ -0,0 +1,31 @@
+package logger
+
+import (
+	"strings"
+
+	"github.com/geomyidia/zylog/internal/paint"
+)
+
+// The ways of showing messages which span several lines (see
+// ZyLogOptions.Multiline).
+const (
+	MultilineRaw    = "raw"
+	MultilineEscape = "escape"
+	MultilineIndent = "indent"
+)
+
+// Format a message, which is to follow the given start of the line, according
+// to the Multiline option.
+func formatMessage(p paint.Painter, c *Colours, start, msg, mode string) string {
+	if !strings.Contains(msg, "\n") {
+		return msg
+	}
+	switch mode {
+	case MultilineEscape:
+		return strings.ReplaceAll(msg, "\n", `\n`)
+	case MultilineIndent:
+		marker := "\n" + strings.Repeat(" ", VisibleWidth(start)) + c.Arrow.paint(p, " │ ")
+		return strings.ReplaceAll(strings.TrimRight(msg, "\n"), "\n", marker)
+	}
+	return msg
+}
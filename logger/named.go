@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// The key under which the name of a named logger (see Named) is given in
+// JSON and slog-text output, and passed to ReplaceAttr.
+const NameKey = "logger"
+
+// Named returns a logger like l whose records carry the given name, shown as
+// a [name] segment after the level in the zylog text format. Naming a named
+// logger joins the names with dots, e.g. repl.reader. For handlers other than
+// zylog's, the name is added as an ordinary attribute.
+func Named(l *slog.Logger, name string) *slog.Logger {
+	if n, ok := l.Handler().(interface {
+		WithName(string) slog.Handler
+	}); ok {
+		return slog.New(n.WithName(name))
+	}
+	return l.With(NameKey, name)
+}
+
+// WithName returns a handler whose records carry the given name, appended to
+// any name the handler already has (see Named).
+func (h *SLogHandler) WithName(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	if h.name != "" {
+		name = h.name + "." + name
+	}
+	h2.name = name
+	return &h2
+}
+
+func (f *fanoutHandler) WithName(name string) slog.Handler {
+	f2 := &fanoutHandler{handlers: make([]slog.Handler, len(f.handlers))}
+	for i, h := range f.handlers {
+		if n, ok := h.(interface {
+			WithName(string) slog.Handler
+		}); ok {
+			f2.handlers[i] = n.WithName(name)
+		} else {
+			f2.handlers[i] = h.WithAttrs([]slog.Attr{slog.String(NameKey, name)})
+		}
+	}
+	return f2
+}
+
+// The minimum level given by the PackageLevels option for a record from a
+// logger with the given name (if any) and caller (if known): by the name, if
+// it's in the map, and otherwise by the caller's package.
+func (pl *packageLevels) forRecord(name string, pc uintptr) (slog.Level, bool) {
+	if name != "" {
+		if level, ok := pl.named(name); ok {
+			return level, true
+		}
+	}
+	if pc != 0 {
+		return pl.level(pc)
+	}
+	return 0, false
+}
+
+// The minimum level given by the PackageLevels option for a named logger: that
+// of the longest name in the map which is the logger's name or one of its
+// parents (repl for repl.reader, say); false if there is no such name.
+func (pl *packageLevels) named(name string) (slog.Level, bool) {
+	var min slog.Level
+	best := -1
+	for n, level := range pl.levels {
+		if len(n) > best && (name == n || strings.HasPrefix(name, n+".")) {
+			min, best = level, len(n)
+		}
+	}
+	return min, best >= 0
+}
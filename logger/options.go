@@ -0,0 +1,13 @@
+package logger
+
+// Default returns the default options: coloured output of INFO and above to
+// stdout, using slog, without caller information. For building options
+// from these with functional options, see zylog.NewOptions.
+func Default() *ZyLogOptions {
+	return &ZyLogOptions{
+		Logger:  Slog,
+		Colored: true,
+		Level:   "info",
+		Output:  "stdout",
+	}
+}
@@ -0,0 +1,238 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// Determine the writer for the configured output(s).
+func outputWriter(opts *ZyLogOptions) (io.Writer, error) {
+	if opts.Writer != nil {
+		return opts.Writer, nil
+	}
+	if len(opts.Outputs) == 0 {
+		return namedOutput(opts.Output, opts)
+	}
+	tee := &teeWriter{}
+	for _, name := range opts.Outputs {
+		w, err := namedOutput(name, opts)
+		if err != nil {
+			return nil, err
+		}
+		if !ResolveColour(opts, w) {
+			w = &plainWriter{w}
+		}
+		tee.writers = append(tee.writers, w)
+	}
+	return tee, nil
+}
+
+// Determine the writer for records at the error level (see errorLevel) and
+// above, if these are to be split from the rest of the output; nil is
+// returned if they aren't, including when ErrorOutput names the one output
+// already in use. Whether the lines written to it are coloured is decided
+// for it alone (see ResolveColour), so that, say, warnings on a terminal are
+// coloured even when the rest of the output is redirected to a file.
+func errorOutputWriter(opts *ZyLogOptions) (io.Writer, error) {
+	if opts.ErrorOutput == "" {
+		return nil, nil
+	}
+	if opts.Writer == nil && len(opts.Outputs) == 0 && opts.ErrorOutput == opts.Output {
+		return nil, nil
+	}
+	return namedOutput(opts.ErrorOutput, opts)
+}
+
+// Determine the writer for a single output name.
+func namedOutput(name string, opts *ZyLogOptions) (io.Writer, error) {
+	switch name {
+	case "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	case "filesystem":
+		if opts.File == "" {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupLogOutput, "filesystem without a File")
+		}
+		var f io.WriteCloser
+		var err error
+		if opts.FileFormat == FileFormatJSONArray {
+			f, err = openJSONArrayFile(opts.File)
+		} else {
+			f, err = openRotatingFile(opts)
+		}
+		if err != nil {
+			return nil, err
+		}
+		track(f)
+		return f, nil
+	case "syslog":
+		sw, err := openSyslog(opts)
+		if err != nil {
+			return nil, err
+		}
+		track(sw)
+		return sw, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupLogOutput, name)
+	}
+}
+
+// A writer which writes each line to all of its writers. A failed write to
+// one writer doesn't prevent the others from being written to; all errors
+// are returned together.
+type teeWriter struct {
+	writers []io.Writer
+}
+
+func (t *teeWriter) Write(p []byte) (int, error) {
+	var errs []error
+	for _, w := range t.writers {
+		if _, err := w.Write(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(p), errors.Join(errs...)
+}
+
+func (t *teeWriter) WriteLevel(level slog.Level, p []byte) (int, error) {
+	var errs []error
+	for _, w := range t.writers {
+		if _, err := writeLevel(w, level, p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(p), errors.Join(errs...)
+}
+
+// A writer which takes the level of the record each line is for into
+// account, e.g. as a syslog severity.
+type levelWriter interface {
+	WriteLevel(level slog.Level, p []byte) (int, error)
+}
+
+// Whether w, or a writer it passes lines on to, makes use of their levels;
+// teeWriter and plainWriter pass them on, but only need them for this.
+func takesLevels(w io.Writer) bool {
+	switch w := w.(type) {
+	case *syslogWriter:
+		return true
+	case *plainWriter:
+		return takesLevels(w.w)
+	case *teeWriter:
+		for _, tw := range w.writers {
+			if takesLevels(tw) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Write a line for a record at the given level to w, passing the level on if
+// w takes it.
+func writeLevel(w io.Writer, level slog.Level, p []byte) (int, error) {
+	if lw, ok := w.(levelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+	return w.Write(p)
+}
+
+// A writer which strips ANSI colour codes before writing.
+type plainWriter struct {
+	w io.Writer
+}
+
+func (pw *plainWriter) Write(p []byte) (int, error) {
+	if _, err := pw.w.Write(ansiEscape.ReplaceAll(p, nil)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (pw *plainWriter) WriteLevel(level slog.Level, p []byte) (int, error) {
+	if _, err := writeLevel(pw.w, level, ansiEscape.ReplaceAll(p, nil)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// A writer which sends logrus entries at errLevel and above to a separate
+// writer, if there is one, and passes their levels on to writers which take
+// them (see levelWriter). The level of the entry being written is recorded by
+// the splitFormatter wrapping the real formatter; this is safe since logrus
+// formats and writes each entry while holding the same lock.
+type splitWriter struct {
+	out      io.Writer
+	errOut   io.Writer
+	errLevel slog.Level
+	level    slog.Level
+}
+
+func (sw *splitWriter) Write(p []byte) (int, error) {
+	if sw.errOut != nil && sw.level >= sw.errLevel {
+		return writeLevel(sw.errOut, sw.level, p)
+	}
+	return writeLevel(sw.out, sw.level, p)
+}
+
+// A formatter which records the level of each entry it formats for its
+// splitWriter.
+type splitFormatter struct {
+	log.Formatter
+	writer *splitWriter
+}
+
+func (sf *splitFormatter) Format(entry *log.Entry) ([]byte, error) {
+	sf.writer.level = logrusToSlogLevel(entry.Level)
+	return sf.Formatter.Format(entry)
+}
+
+// A formatter which formats the entries at errLevel and above, which go to
+// the error output, with err, and the others with out, so that each output
+// gets its own colouring.
+type errorFormatter struct {
+	out      log.Formatter
+	err      log.Formatter
+	errLevel slog.Level
+}
+
+func (ef *errorFormatter) Format(entry *log.Entry) ([]byte, error) {
+	if logrusToSlogLevel(entry.Level) >= ef.errLevel {
+		return ef.err.Format(entry)
+	}
+	return ef.out.Format(entry)
+}
+
+// Return a formatter which formats entries as text does, except that those
+// going to the error output errOut, if any, are coloured as ResolveColour
+// decides for it.
+func withErrorColours(opts *ZyLogOptions, text *TextFormatter, errOut io.Writer) log.Formatter {
+	if errOut == nil || ResolveColour(opts, errOut) != text.DisableColors {
+		return text
+	}
+	errText := *text
+	errText.DisableColors = !text.DisableColors
+	return &errorFormatter{out: text, err: &errText, errLevel: errorLevel(opts)}
+}
+
+// The lowest level of the records sent to ErrorOutput: given by the
+// ErrorLevel option, or WARNING.
+func errorLevel(opts *ZyLogOptions) slog.Level {
+	if opts.ErrorLevel == "" {
+		return slog.LevelWarn
+	}
+	level, err := parseSlogLevel(opts.ErrorLevel)
+	if err != nil {
+		return slog.LevelWarn
+	}
+	return level
+}
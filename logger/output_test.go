@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// A file which isn't a terminal, so isn't coloured.
+func tempFile(t *testing.T) *os.File {
+	t.Helper()
+	f, err := os.Create(filepath.Join(t.TempDir(), "log.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func clearColourEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+}
+
+func TestErrorOutputColour(t *testing.T) {
+	clearColourEnv(t)
+	for _, errColoured := range []bool{true, false} {
+		file := tempFile(t)
+		var buf bytes.Buffer
+		// The file isn't coloured, and the buffer is.
+		var out, errOut io.Writer = file, &buf
+		if !errColoured {
+			out, errOut = errOut, out
+		}
+		h, err := NewSLogHandler(out, Default())
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.setErrorOutput(errOut)
+		l := slog.New(h)
+		l.Info("to the output")
+		l.Warn("to the error output")
+		data, err := os.ReadFile(file.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		coloured, plain := buf.String(), string(data)
+		colouredMsg, plainMsg := "to the error output", "to the output"
+		if !errColoured {
+			colouredMsg, plainMsg = plainMsg, colouredMsg
+		}
+		if !strings.Contains(coloured, colouredMsg) || !strings.Contains(coloured, "\x1b[") {
+			t.Errorf("errColoured=%v: %q, want %q coloured", errColoured, coloured, colouredMsg)
+		}
+		if !strings.Contains(plain, plainMsg) || strings.Contains(plain, "\x1b[") {
+			t.Errorf("errColoured=%v: %q, want %q uncoloured", errColoured, plain, plainMsg)
+		}
+	}
+}
+
+func TestErrorOutputColourLogrus(t *testing.T) {
+	clearColourEnv(t)
+	opts := Default()
+	file := tempFile(t)
+	text := &TextFormatter{DisableColors: !ResolveColour(opts, file), Colours: DefaultColours()}
+	f := withErrorColours(opts, text, &bytes.Buffer{})
+	entry := log.NewEntry(log.New())
+	entry.Level, entry.Message = log.InfoLevel, "to the output"
+	info, err := f.Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(info, []byte("\x1b[")) {
+		t.Errorf("output = %q, want it uncoloured", info)
+	}
+	entry.Level, entry.Message = log.WarnLevel, "to the error output"
+	warn, err := f.Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(warn, []byte("\x1b[93mWARNING\x1b[0m")) {
+		t.Errorf("error output = %q, want it coloured", warn)
+	}
+	if withErrorColours(opts, text, tempFile(t)) != log.Formatter(text) {
+		t.Error("an error output coloured as the output got its own formatter")
+	}
+}
+
+func TestErrorOutputSplit(t *testing.T) {
+	tests := []struct {
+		name       string
+		backend    Backend
+		errorLevel string
+		out, err   []string
+	}{
+		{name: "slog", backend: Slog,
+			out: []string{"debug line", "info line"}, err: []string{"warn line", "error line"}},
+		{name: "slog error level", backend: Slog, errorLevel: "error",
+			out: []string{"debug line", "info line", "warn line"}, err: []string{"error line"}},
+		{name: "logrus", backend: LogRUs,
+			out: []string{"debug line", "info line"}, err: []string{"warn line", "error line"}},
+		{name: "logrus error level", backend: LogRUs, errorLevel: "error",
+			out: []string{"debug line", "info line", "warn line"}, err: []string{"error line"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "errors.txt")
+			var buf bytes.Buffer
+			opts := Default()
+			opts.Level = "debug"
+			opts.Logger = tt.backend
+			opts.Writer = &buf
+			opts.ErrorOutput = "filesystem"
+			opts.File = path
+			opts.ErrorLevel = tt.errorLevel
+			if tt.backend == Slog {
+				defer slog.SetDefault(slog.Default())
+				l, err := SetupSlog(opts)
+				if err != nil {
+					t.Fatalf("SetupSlog: %v", err)
+				}
+				l.Debug("debug line")
+				l.Info("info line")
+				l.Warn("warn line")
+				l.Error("error line")
+			} else {
+				if err := SetupLogRUs(opts); err != nil {
+					t.Fatalf("SetupLogRUs: %v", err)
+				}
+				log.Debug("debug line")
+				log.Info("info line")
+				log.Warn("warn line")
+				log.Error("error line")
+			}
+			if err := Shutdown(context.Background()); err != nil {
+				t.Fatalf("Shutdown: %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, errOut := buf.String(), string(data)
+			for _, msg := range tt.out {
+				if !strings.Contains(out, msg) || strings.Contains(errOut, msg) {
+					t.Errorf("%q not only in the output:\noutput: %q\nerror output: %q", msg, out, errOut)
+				}
+			}
+			for _, msg := range tt.err {
+				if !strings.Contains(errOut, msg) || strings.Contains(out, msg) {
+					t.Errorf("%q not only in the error output:\noutput: %q\nerror output: %q", msg, out, errOut)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package logger
+
+import "strings"
+
+// Return the spaces which align the (upper-case) level name with the widest of
+// the levels named in pad. Levels not named in pad aren't padded, so that rare
+// levels such as PANIC needn't widen every line.
+func levelPadding(level string, pad []string) string {
+	width, listed := 0, false
+	for _, name := range pad {
+		l, err := parseSlogLevel(name)
+		if err != nil {
+			continue
+		}
+		name = slogLevelToString(l)
+		if len(name) > width {
+			width = len(name)
+		}
+		if name == level {
+			listed = true
+		}
+	}
+	if !listed {
+		return ""
+	}
+	return strings.Repeat(" ", width-len(level))
+}
@@ -0,0 +1,19 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Write the process ID to the configured pid file, if any.
+func writePidFile(opts *ZyLogOptions) error {
+	if opts.PidFile == "" {
+		return nil
+	}
+	pid := strconv.Itoa(os.Getpid()) + "\n"
+	if err := os.WriteFile(opts.PidFile, []byte(pid), 0o644); err != nil {
+		return fmt.Errorf("could not write pid file: %w", err)
+	}
+	return nil
+}
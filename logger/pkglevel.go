@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// The minimum levels given by the PackageLevels option.
+type packageLevels struct {
+	levels map[string]slog.Level
+	// The lowest of the levels.
+	min slog.Level
+	// The level found for each caller, by its program counter.
+	callers sync.Map
+}
+
+type callerLevel struct {
+	level slog.Level
+	ok    bool
+}
+
+// The package levels given by the options, or nil if there are none.
+func newPackageLevels(opts *ZyLogOptions) *packageLevels {
+	if len(opts.PackageLevels) == 0 {
+		return nil
+	}
+	pl := &packageLevels{levels: make(map[string]slog.Level)}
+	first := true
+	for pkg, name := range opts.PackageLevels {
+		level, err := parseSlogLevel(name)
+		if err != nil {
+			continue
+		}
+		pl.levels[pkg] = level
+		if first || level < pl.min {
+			pl.min, first = level, false
+		}
+	}
+	return pl
+}
+
+// The minimum level for the package of the caller with the given program
+// counter, which is that of the longest package path in the map which is the
+// caller's package or one of its parents; false if there is no such path.
+func (pl *packageLevels) level(pc uintptr) (slog.Level, bool) {
+	if cl, ok := pl.callers.Load(pc); ok {
+		return cl.(callerLevel).level, cl.(callerLevel).ok
+	}
+	f, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	pkg := packageOf(f.Function)
+	var cl callerLevel
+	best := -1
+	for path, level := range pl.levels {
+		if len(path) > best && (pkg == path || strings.HasPrefix(pkg, path+"/")) {
+			cl, best = callerLevel{level: level, ok: true}, len(path)
+		}
+	}
+	pl.callers.Store(pc, cl)
+	return cl.level, cl.ok
+}
+
+// The package path of a function named as by runtime.Frame, e.g.
+// github.com/a/b for github.com/a/b.(*T).Method.
+func packageOf(function string) string {
+	slash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[slash+1:], ".")
+	if dot < 0 {
+		return function
+	}
+	return function[:slash+1+dot]
+}
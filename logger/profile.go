@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime/pprof"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Cumulative time spent by slog handlers with the ProfileLabels option set.
+var (
+	renderNanos atomic.Int64
+	writeNanos  atomic.Int64
+)
+
+// The pprof labels for the two phases of handling a record. Work done by the
+// slog handler is labelled with:
+//
+//	zylog_phase   render or write
+//	zylog_format  the output format (see FileFormat)
+//	zylog_dest    the output destination(s)
+type profileLabels struct {
+	render pprof.LabelSet
+	write  pprof.LabelSet
+}
+
+func newProfileLabels(opts *ZyLogOptions, format string) *profileLabels {
+	dest := profileDest(opts)
+	return &profileLabels{
+		render: pprof.Labels("zylog_phase", "render", "zylog_format", format, "zylog_dest", dest),
+		write:  pprof.Labels("zylog_phase", "write", "zylog_format", format, "zylog_dest", dest),
+	}
+}
+
+// CPUCost returns the cumulative time spent rendering and writing records by
+// slog handlers created with the ProfileLabels option. The times are
+// measured with the wall clock, so time spent blocked in writes is included.
+func CPUCost() (render, write time.Duration) {
+	return time.Duration(renderNanos.Load()), time.Duration(writeNanos.Load())
+}
+
+// Handle a record under pprof labels, accounting for the time taken.
+func (h *SLogHandler) profiledHandle(ctx context.Context, r slog.Record) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var line string
+	var err error
+	start := time.Now()
+	pprof.Do(ctx, h.profile.render, func(ctx context.Context) {
+		line = h.render(ctx, r)
+	})
+	rendered := time.Now()
+	pprof.Do(ctx, h.profile.write, func(context.Context) {
+		err = h.write(r.Level, line)
+	})
+	renderNanos.Add(int64(rendered.Sub(start)))
+	writeNanos.Add(int64(time.Since(rendered)))
+	return err
+}
+
+// Describe the configured output destination(s) for the zylog_dest label.
+func profileDest(opts *ZyLogOptions) string {
+	switch {
+	case opts.Writer != nil:
+		return "writer"
+	case len(opts.Outputs) > 0:
+		return strings.Join(opts.Outputs, ",")
+	}
+	return opts.Output
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"runtime/pprof"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The labels on the goroutine profile, waiting until one has the given
+// phase.
+func profileWithPhase(t *testing.T, phase string) string {
+	t.Helper()
+	want := `"zylog_phase":"` + phase + `"`
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var buf bytes.Buffer
+		if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range strings.Split(buf.String(), "\n") {
+			if strings.HasPrefix(line, "# labels:") && strings.Contains(line, want) {
+				return line
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("no goroutine labelled %s:\n%s", want, buf.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestProfileLabels(t *testing.T) {
+	inRender := make(chan struct{})
+	releaseRender := make(chan struct{})
+	sw := &stuckWriter{release: make(chan struct{})}
+	opts := Default()
+	opts.ProfileLabels = true
+	opts.Writer = sw
+	opts.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == "block" {
+			close(inRender)
+			<-releaseRender
+		}
+		return a
+	}
+	h, err := NewSLogHandler(sw, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		slog.New(h).Info("profiled", "block", true)
+	}()
+
+	<-inRender
+	labels := profileWithPhase(t, "render")
+	for _, want := range []string{`"zylog_format":"text"`, `"zylog_dest":"writer"`} {
+		if !strings.Contains(labels, want) {
+			t.Errorf("render labels %s lack %s", labels, want)
+		}
+	}
+	close(releaseRender)
+	labels = profileWithPhase(t, "write")
+	if !strings.Contains(labels, `"zylog_dest":"writer"`) {
+		t.Errorf("write labels %s lack the destination", labels)
+	}
+	close(sw.release)
+	<-done
+
+	render, write := CPUCost()
+	if render <= 0 || write <= 0 {
+		t.Errorf("CPUCost() = %v, %v, want both positive", render, write)
+	}
+}
@@ -0,0 +1,134 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The message of the line noting the records dropped by the RateLimits
+// option, and the key of the attribute (or logrus field) giving their number.
+const (
+	SuppressedMsg = "Messages suppressed by the rate limit"
+	SuppressedKey = "suppressed"
+)
+
+// A token bucket for each level limited by the RateLimits option; levels
+// without one aren't limited. It is safe for concurrent use.
+type rateLimiter struct {
+	buckets map[slog.Level]*tokenBucket
+}
+
+// A bucket holding up to rate tokens, refilled at rate tokens a second, one
+// being taken for each record logged.
+type tokenBucket struct {
+	mu      sync.Mutex
+	rate    float64
+	tokens  float64
+	last    time.Time
+	dropped int
+}
+
+// Create the rate limiter asked for by the options; nil if none is. Unknown
+// level names (which Validate rejects) are ignored.
+func newRateLimiter(opts *ZyLogOptions) *rateLimiter {
+	if len(opts.RateLimits) == 0 {
+		return nil
+	}
+	rl := &rateLimiter{buckets: make(map[slog.Level]*tokenBucket)}
+	for name, rate := range opts.RateLimits {
+		level, err := parseSlogLevel(name)
+		if err != nil || rate <= 0 {
+			continue
+		}
+		rl.buckets[level] = &tokenBucket{rate: float64(rate), tokens: float64(rate)}
+	}
+	return rl
+}
+
+// Whether a record at the given level may be logged now; if it may, the
+// number of records at the level dropped since the last one allowed is
+// returned too.
+func (rl *rateLimiter) allow(level slog.Level) (bool, int) {
+	b, ok := rl.buckets[level]
+	if !ok {
+		return true, 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.rate {
+			b.tokens = b.rate
+		}
+	}
+	b.last = now
+	if b.tokens < 1 {
+		b.dropped++
+		return false, 0
+	}
+	b.tokens--
+	dropped := b.dropped
+	b.dropped = 0
+	return true, dropped
+}
+
+// Check the level names and rates of the RateLimits option.
+func validateRateLimits(limits map[string]int) error {
+	for name, rate := range limits {
+		if _, err := parseSlogLevel(name); err != nil {
+			return fmt.Errorf("%w: rate limit for %s", err, name)
+		}
+		if rate <= 0 {
+			return fmt.Errorf("%w: rate limit %d for %s", ErrInvalidOption, rate, name)
+		}
+	}
+	return nil
+}
+
+// The record noting that dropped records at the level of r were dropped,
+// logged just before r.
+func suppressedRecord(r slog.Record, dropped int) slog.Record {
+	s := slog.NewRecord(r.Time, r.Level, SuppressedMsg, 0)
+	s.AddAttrs(slog.Int(SuppressedKey, dropped))
+	return s
+}
+
+// A logrus formatter which gives logrus nothing to write for the entries the
+// rate limiter drops, and, with the RateLimitSummary option, notes how many
+// were dropped before the next entry it allows. Hooks have already fired for
+// the entries dropped.
+type limitingFormatter struct {
+	log.Formatter
+	limiter *rateLimiter
+	summary bool
+}
+
+func (lf *limitingFormatter) Format(entry *log.Entry) ([]byte, error) {
+	ok, dropped := lf.limiter.allow(logrusToSlogLevel(entry.Level))
+	if !ok {
+		return nil, nil
+	}
+	if dropped == 0 || !lf.summary {
+		return lf.Formatter.Format(entry)
+	}
+	s := *entry
+	s.Buffer = nil
+	s.Data = log.Fields{SuppressedKey: dropped}
+	s.Message = SuppressedMsg
+	// Without the entry's buffer, the summary is formatted into one of its
+	// own.
+	summary, err := lf.Formatter.Format(&s)
+	if err != nil {
+		return nil, err
+	}
+	b, err := lf.Formatter.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+	return append(summary, b...), nil
+}
@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Redacted is what the values of attributes (and logrus fields) matched by
+// the RedactKeys and RedactPattern options are replaced with.
+const Redacted = "***"
+
+// The keys whose values are redacted, as given by the options.
+type redactor struct {
+	keys    []string
+	pattern *regexp.Regexp
+}
+
+// Create the redactor asked for by the options; nil if none is. A pattern
+// which doesn't compile (which Validate rejects) is ignored.
+func newRedactor(opts *ZyLogOptions) *redactor {
+	if len(opts.RedactKeys) == 0 && opts.RedactPattern == "" {
+		return nil
+	}
+	r := &redactor{keys: opts.RedactKeys}
+	if opts.RedactPattern != "" {
+		r.pattern, _ = compileRedactPattern(opts.RedactPattern)
+	}
+	return r
+}
+
+// Compile the RedactPattern option, which ignores case.
+func compileRedactPattern(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: redact pattern %q: %v", ErrInvalidOption, pattern, err)
+	}
+	return re, nil
+}
+
+// Whether the value of the attribute with the given key, in the given
+// groups, is redacted: it is if the key, the name of one of the groups, or
+// the dotted path to either (e.g. "auth.password") matches.
+func (r *redactor) matches(groups []string, key string) bool {
+	var path strings.Builder
+	match := func(name string) bool {
+		if path.Len() > 0 {
+			path.WriteByte('.')
+		}
+		path.WriteString(name)
+		return r.match(name) || r.match(path.String())
+	}
+	for _, g := range groups {
+		if match(g) {
+			return true
+		}
+	}
+	return match(key)
+}
+
+func (r *redactor) match(s string) bool {
+	for _, k := range r.keys {
+		if strings.EqualFold(k, s) {
+			return true
+		}
+	}
+	return r.pattern != nil && r.pattern.MatchString(s)
+}
+
+// Redact the attribute's value if its key matches.
+func (r *redactor) redact(groups []string, a slog.Attr) slog.Attr {
+	if r != nil && a.Value.Kind() != slog.KindGroup && r.matches(groups, a.Key) {
+		a.Value = slog.StringValue(Redacted)
+	}
+	return a
+}
+
+// A logrus hook which redacts the values of the fields whose keys match;
+// dotted keys are matched as paths, as for group members with slog.
+type redactHook struct {
+	redactor *redactor
+}
+
+func (rh *redactHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (rh *redactHook) Fire(entry *log.Entry) error {
+	var data log.Fields
+	for k := range entry.Data {
+		path := strings.Split(k, ".")
+		if !rh.redactor.matches(path[:len(path)-1], path[len(path)-1]) {
+			continue
+		}
+		if data == nil {
+			// As for stackHook, the fields are copied rather than changed.
+			data = make(log.Fields, len(entry.Data))
+			for k, v := range entry.Data {
+				data[k] = v
+			}
+		}
+		data[k] = Redacted
+	}
+	if data != nil {
+		entry.Data = data
+	}
+	return nil
+}
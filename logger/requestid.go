@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+// RequestIDKey is the attribute key under which the slog handler logs the
+// request ID carried by a record's context.
+const RequestIDKey = "request_id"
+
+// IDFunc generates the request IDs used by EnsureRequestID; by default it
+// returns a random (version 4) UUID. Replace it during program initialisation
+// to use another scheme, e.g. ULIDs or KSUIDs.
+var IDFunc = newUUID
+
+type requestIDCtxKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the given request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, if any.
+func RequestID(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	id, ok := ctx.Value(requestIDCtxKey{}).(string)
+	return id, ok && id != ""
+}
+
+// EnsureRequestID returns a context guaranteed to carry a request ID: ctx
+// itself if it already has one, otherwise a copy carrying an ID generated by
+// IDFunc.
+func EnsureRequestID(ctx context.Context) context.Context {
+	if _, ok := RequestID(ctx); ok {
+		return ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return WithRequestID(ctx, IDFunc())
+}
+
+// Generate a random (version 4) UUID.
+func newUUID() string {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		panic(err)
+	}
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+}
@@ -0,0 +1,332 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The timestamp added to the names of rotated files; it sorts
+// chronologically.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// A file writer which rotates the file when it reaches a maximum size. A
+// rotated file is renamed with a timestamp between its base name and its
+// extension, e.g. app-2019-03-15T10-20-30.000.log (with a counter after the
+// timestamp, e.g. -1, if a file was already rotated within the same
+// millisecond), optionally gzipped, and pruned once there are too many
+// rotated files or they become too old. Each rotation is logged (see
+// setRotationLogger). Compressing, pruning, and logging are done in the
+// background, after the write which rotated the file; an error from them is
+// returned by the next Write (or Close).
+type rotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	maxAge     time.Duration
+	compress   bool
+	file       *os.File
+	size       int64
+	// Set while a rotation is being finished in the background.
+	finishing atomic.Bool
+	// The error from finishing the last rotation, if any, which hasn't yet
+	// been returned.
+	finishErr error
+}
+
+// Logs the rotation of a file to the logger set up last, with the name the
+// file was rotated to, its own name, and what triggered the rotation.
+var rotationLogger atomic.Pointer[func(old, new, trigger string)]
+
+// Have rotations logged with the given function, by the setup functions.
+func setRotationLogger(fn func(old, new, trigger string)) {
+	rotationLogger.Store(&fn)
+}
+
+// The number of rotations being logged, which Flush waits for.
+var rotations = struct {
+	mu      sync.Mutex
+	done    *sync.Cond
+	pending int
+}{}
+
+func init() {
+	rotations.done = sync.NewCond(&rotations.mu)
+}
+
+// Wait until the rotations being logged have been logged.
+func waitForRotations() {
+	rotations.mu.Lock()
+	defer rotations.mu.Unlock()
+	for rotations.pending > 0 {
+		rotations.done.Wait()
+	}
+}
+
+func openRotatingFile(opts *ZyLogOptions) (*rotatingFile, error) {
+	rf := &rotatingFile{
+		path:       opts.File,
+		maxSize:    int64(opts.MaxSizeMB) * 1024 * 1024,
+		maxBackups: opts.MaxBackups,
+		maxAge:     time.Duration(opts.MaxAgeDays) * 24 * time.Hour,
+		compress:   opts.Compress,
+	}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	var backup string
+	var rotateErr error
+	if rf.file == nil {
+		// Reopening failed in an earlier rotation.
+		if err := rf.open(); err != nil {
+			rf.mu.Unlock()
+			return 0, err
+		}
+	} else if rf.maxSize > 0 && rf.size > 0 && rf.size+int64(len(p)) > rf.maxSize && !rf.finishing.Load() {
+		backup, rotateErr = rf.rotate()
+		if rf.file == nil {
+			rf.mu.Unlock()
+			return 0, rotateErr
+		}
+	}
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+	if err == nil {
+		err, rf.finishErr = rf.finishErr, nil
+	}
+	rf.mu.Unlock()
+	if backup != "" {
+		rf.finish(backup, "size")
+	}
+	return n, err
+}
+
+// Finish the rotation of the file to backup in another goroutine: gzip it if
+// asked to, prune the rotated files, and log the rotation, at INFO. This keeps
+// compression off the write path, and the rotation may have happened in a
+// write by a bufferedWriter, which would have to finish before the record
+// logging it could be written. Until the rotation is finished, the file isn't
+// rotated again, so that logging a rotation can't itself rotate the file.
+func (rf *rotatingFile) finish(backup, trigger string) {
+	rf.finishing.Store(true)
+	rotations.mu.Lock()
+	rotations.pending++
+	rotations.mu.Unlock()
+	go func() {
+		defer func() {
+			rf.finishing.Store(false)
+			rotations.mu.Lock()
+			rotations.pending--
+			rotations.done.Broadcast()
+			rotations.mu.Unlock()
+		}()
+		var err error
+		if rf.compress {
+			if err = gzipFile(backup); err == nil {
+				backup += ".gz"
+			}
+		}
+		ext := filepath.Ext(rf.path)
+		if pruneErr := rf.prune(strings.TrimSuffix(rf.path, ext)+"-", ext); err == nil {
+			err = pruneErr
+		}
+		if err != nil {
+			rf.mu.Lock()
+			rf.finishErr = err
+			rf.mu.Unlock()
+		}
+		if logRotation := rotationLogger.Load(); logRotation != nil {
+			(*logRotation)(backup, rf.path, trigger)
+		}
+	}()
+}
+
+// Close closes the current file, returning any error from finishing the last
+// rotation which hasn't yet been returned.
+func (rf *rotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	err := rf.finishErr
+	rf.finishErr = nil
+	if rf.file != nil {
+		if closeErr := rf.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func (rf *rotatingFile) open() error {
+	f, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	rf.file = f
+	rf.size = info.Size()
+	return nil
+}
+
+// Move the current file aside and start a new one, returning the name the
+// file was moved to; the rotation is then finished by finish. If the file
+// can't be moved, writing carries on with it; if the new one can't be opened,
+// rf.file is left nil.
+func (rf *rotatingFile) rotate() (string, error) {
+	if err := rf.file.Close(); err != nil {
+		if rf.open() != nil {
+			rf.file = nil
+		}
+		return "", err
+	}
+	ext := filepath.Ext(rf.path)
+	prefix := strings.TrimSuffix(rf.path, ext) + "-"
+	backup := backupName(prefix+time.Now().Format(backupTimeFormat), ext)
+	renameErr := os.Rename(rf.path, backup)
+	if err := rf.open(); err != nil {
+		rf.file = nil
+		return "", err
+	}
+	if renameErr != nil {
+		return "", renameErr
+	}
+	return backup, nil
+}
+
+// The name of a rotated file with the given name, before its extension:
+// name+ext, unless a rotated file (gzipped or not) already has that name, as
+// it will if the file was rotated within the same millisecond, in which case
+// a counter is added, e.g. -1.
+func backupName(name, ext string) string {
+	backup := name + ext
+	for i := 1; exists(backup) || exists(backup+".gz"); i++ {
+		backup = fmt.Sprintf("%s-%d%s", name, i, ext)
+	}
+	return backup
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// Remove the rotated files beyond the number to keep, and those which are
+// too old.
+func (rf *rotatingFile) prune(prefix, ext string) error {
+	if rf.maxBackups <= 0 && rf.maxAge <= 0 {
+		return nil
+	}
+	matches, err := filepath.Glob(prefix + "*")
+	if err != nil {
+		return err
+	}
+	type rotated struct {
+		name    string
+		stamp   time.Time
+		counter int
+	}
+	var found []rotated
+	for _, m := range matches {
+		name := strings.TrimSuffix(m, ".gz")
+		if !strings.HasSuffix(name, ext) {
+			continue
+		}
+		stamp, counter, ok := parseBackupStamp(strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext))
+		if ok {
+			found = append(found, rotated{m, stamp, counter})
+		}
+	}
+	// Newest first.
+	sort.Slice(found, func(i, j int) bool {
+		if !found[i].stamp.Equal(found[j].stamp) {
+			return found[i].stamp.After(found[j].stamp)
+		}
+		return found[i].counter > found[j].counter
+	})
+	backups := make([]string, len(found))
+	for i, r := range found {
+		backups[i] = r.name
+	}
+	cutoff := time.Now().Add(-rf.maxAge)
+	for i, b := range backups {
+		remove := rf.maxBackups > 0 && i >= rf.maxBackups
+		if !remove && rf.maxAge > 0 {
+			if info, err := os.Stat(b); err == nil && info.ModTime().Before(cutoff) {
+				remove = true
+			}
+		}
+		if remove {
+			if err := os.Remove(b); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Parse the timestamp of a rotated file, followed by any counter (see
+// backupName), reporting whether s is one.
+func parseBackupStamp(s string) (time.Time, int, bool) {
+	if len(s) < len(backupTimeFormat) {
+		return time.Time{}, 0, false
+	}
+	stamp, err := time.Parse(backupTimeFormat, s[:len(backupTimeFormat)])
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	counter := s[len(backupTimeFormat):]
+	if counter == "" {
+		return stamp, 0, true
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(counter, "-"))
+	if !strings.HasPrefix(counter, "-") || err != nil || n <= 0 {
+		return time.Time{}, 0, false
+	}
+	return stamp, n, true
+}
+
+// Replace a file with a gzipped copy.
+func gzipFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
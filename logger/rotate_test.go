@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBackupName(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "app-2019-03-15T10-20-30.000")
+	if got, want := backupName(name, ".log"), name+".log"; got != want {
+		t.Errorf("backupName = %s, want %s", got, want)
+	}
+	touch(t, name+".log")
+	if got, want := backupName(name, ".log"), name+"-1.log"; got != want {
+		t.Errorf("backupName = %s, want %s", got, want)
+	}
+	touch(t, name+"-1.log.gz")
+	if got, want := backupName(name, ".log"), name+"-2.log"; got != want {
+		t.Errorf("backupName = %s, want %s", got, want)
+	}
+}
+
+func TestPruneCounters(t *testing.T) {
+	dir := t.TempDir()
+	prefix := filepath.Join(dir, "app-")
+	older := prefix + "2019-03-15T10-20-29.999.log"
+	first := prefix + "2019-03-15T10-20-30.000.log"
+	second := prefix + "2019-03-15T10-20-30.000-1.log"
+	for _, name := range []string{older, first, second} {
+		touch(t, name)
+	}
+	rf := &rotatingFile{maxBackups: 2}
+	if err := rf.prune(prefix, ".log"); err != nil {
+		t.Fatal(err)
+	}
+	if exists(older) {
+		t.Errorf("%s kept", older)
+	}
+	for _, name := range []string{first, second} {
+		if !exists(name) {
+			t.Errorf("%s removed", name)
+		}
+	}
+}
+
+func TestRotationLogged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	opts := Default()
+	opts.Output = "filesystem"
+	opts.File = path
+	opts.MaxSizeMB = 1
+	defer slog.SetDefault(slog.Default())
+	logger, err := SetupSlog(opts)
+	if err != nil {
+		t.Fatalf("SetupSlog: %v", err)
+	}
+	defer Shutdown(context.Background())
+	big := strings.Repeat("x", 600*1024)
+	logger.Info(big)
+	logger.Info(big)
+	if err := Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	backups, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "app-*.log"))
+	if len(backups) != 1 {
+		t.Fatalf("rotated files: %v", backups)
+	}
+	for _, want := range []string{"Log file rotated.", "old_file={" + backups[0] + "}", "new_file={" + path + "}", "trigger={size}"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q", want)
+		}
+	}
+}
+
+func touch(t *testing.T, path string) {
+	t.Helper()
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRotationErrorKeepsRecord(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	// An old rotated "file" which can't be pruned: a directory with
+	// something in it.
+	stuck := filepath.Join(dir, "app-2019-03-15T10-20-30.000.log")
+	if err := os.Mkdir(stuck, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	touch(t, filepath.Join(stuck, "keep"))
+	opts := Default()
+	opts.File = path
+	opts.MaxSizeMB = 1
+	opts.MaxBackups = 1
+	rf, err := openRotatingFile(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rf.Close()
+	big := strings.Repeat("x", 600*1024) + "\n"
+	for _, line := range []string{"first " + big, "second " + big} {
+		if _, err := rf.Write([]byte(line)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	waitForRotations()
+	n, err := rf.Write([]byte("third\n"))
+	if err == nil {
+		t.Error("the pruning error wasn't returned")
+	}
+	if n != len("third\n") {
+		t.Errorf("Write = %d, want the record written", n)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "second ") || !strings.HasSuffix(string(data), "third\n") {
+		t.Errorf("log file holds %.20q...%q", data, data[len(data)-10:])
+	}
+	if _, err := rf.Write([]byte("fourth\n")); err != nil {
+		t.Errorf("error returned again: %v", err)
+	}
+}
+
+func TestRotationCompressed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	opts := Default()
+	opts.Output = "filesystem"
+	opts.File = path
+	opts.MaxSizeMB = 1
+	opts.Compress = true
+	defer slog.SetDefault(slog.Default())
+	logger, err := SetupSlog(opts)
+	if err != nil {
+		t.Fatalf("SetupSlog: %v", err)
+	}
+	defer Shutdown(context.Background())
+	big := strings.Repeat("x", 600*1024)
+	logger.Info(big)
+	logger.Info(big)
+	if err := Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	dir := filepath.Dir(path)
+	if plain, _ := filepath.Glob(filepath.Join(dir, "app-*.log")); len(plain) != 0 {
+		t.Errorf("uncompressed rotated files: %v", plain)
+	}
+	gzipped, _ := filepath.Glob(filepath.Join(dir, "app-*.log.gz"))
+	if len(gzipped) != 1 {
+		t.Fatalf("compressed rotated files: %v", gzipped)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "old_file={" + gzipped[0] + "}"; !strings.Contains(string(data), want) {
+		t.Errorf("missing %q", want)
+	}
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Send the entries of the logrus standard logger to the given slog handler,
+// rather than having logrus format and write them.
+func routeLogRUs(h slog.Handler, opts *ZyLogOptions) {
+	level, _ := opts.ParsedLevel()
+	log.SetLevel(level.LogRUs())
+	log.SetOutput(io.Discard)
+	log.SetFormatter(&slogFormatter{handler: h})
+	log.SetReportCaller(opts.ReportCaller)
+	// The handler adds any stack traces, and skips callers, itself; logrus
+	// need only find the caller to skip from.
+	setHooks(nil)
+	if opts.ReportCaller && opts.CallerSkip > 0 {
+		log.AddHook(&callerHook{})
+	}
+	for _, h := range opts.LogrusHooks {
+		log.AddHook(&passedHook{h})
+	}
+}
+
+// A logrus formatter which hands each entry to an slog handler, as a record,
+// and gives logrus nothing to write.
+type slogFormatter struct {
+	handler slog.Handler
+}
+
+func (f *slogFormatter) Format(entry *log.Entry) ([]byte, error) {
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	level := logrusToSlogLevel(entry.Level)
+	if !f.handler.Enabled(ctx, level) {
+		return nil, nil
+	}
+	var pc uintptr
+	if entry.Caller != nil {
+		// Frame.PC is that of the call itself; records hold return addresses.
+		pc = entry.Caller.PC + 1
+	}
+	r := slog.NewRecord(entry.Time, level, entry.Message, pc)
+	r.AddAttrs(FieldsToAttrs(entry.Data)...)
+	return nil, f.handler.Handle(ctx, r)
+}
+
+func logrusToSlogLevel(level log.Level) slog.Level {
+	switch level {
+	case log.PanicLevel:
+		return LevelPanic
+	case log.FatalLevel:
+		return LevelFatal
+	case log.ErrorLevel:
+		return slog.LevelError
+	case log.WarnLevel:
+		return slog.LevelWarn
+	case log.InfoLevel:
+		return slog.LevelInfo
+	case log.DebugLevel:
+		return slog.LevelDebug
+	}
+	return LevelTrace
+}
+
+// The logrus level for an slog level. Levels above ERROR map to ERROR, since
+// logging at FATAL or PANIC would exit or panic.
+func slogToLogrusLevel(level slog.Level) log.Level {
+	switch {
+	case level < slog.LevelDebug:
+		return log.TraceLevel
+	case level < slog.LevelInfo:
+		return log.DebugLevel
+	case level < slog.LevelWarn:
+		return log.InfoLevel
+	case level < slog.LevelError:
+		return log.WarnLevel
+	}
+	return log.ErrorLevel
+}
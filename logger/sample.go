@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Sampling limits how often the same message is logged (see the Sampling
+// option): of the records with the same level and message in each Tick (by
+// default, a second), the first Initial are logged, and then every
+// Thereafter-th (none, if Thereafter is 0); the rest are dropped.
+type Sampling struct {
+	Initial    int           `json:"initial" yaml:"initial"`
+	Thereafter int           `json:"thereafter" yaml:"thereafter"`
+	Tick       time.Duration `json:"tick" yaml:"tick"`
+}
+
+// The number of counts kept by a sampler. Messages are counted by a hash of
+// their level and text, so that unrelated messages whose hashes collide are
+// sampled together; with this many, that is rare.
+const sampleCounts = 4096
+
+// The counts of the records seen by the Sampling option; it is safe for
+// concurrent use, without locking.
+type sampler struct {
+	initial    uint64
+	thereafter uint64
+	tick       int64
+	counts     [sampleCounts]sampleCount
+}
+
+type sampleCount struct {
+	// When the count starts again, in nanoseconds since the Unix epoch.
+	resetAt atomic.Int64
+	n       atomic.Uint64
+}
+
+// Create the sampler asked for by the options; nil if none is.
+func newSampler(opts *ZyLogOptions) *sampler {
+	if opts.Sampling == nil {
+		return nil
+	}
+	tick := opts.Sampling.Tick
+	if tick <= 0 {
+		tick = time.Second
+	}
+	return &sampler{
+		initial:    uint64(opts.Sampling.Initial),
+		thereafter: uint64(opts.Sampling.Thereafter),
+		tick:       int64(tick),
+	}
+}
+
+// Whether a record with the given level and message is to be logged.
+func (s *sampler) keep(level slog.Level, msg string) bool {
+	n := s.counts[sampleKey(level, msg)%sampleCounts].inc(time.Now().UnixNano(), s.tick)
+	if n <= s.initial {
+		return true
+	}
+	return s.thereafter > 0 && (n-s.initial)%s.thereafter == 0
+}
+
+// Count a record seen at now, starting again if the tick it was counting has
+// passed, and return the count.
+func (c *sampleCount) inc(now, tick int64) uint64 {
+	resetAt := c.resetAt.Load()
+	if now < resetAt {
+		return c.n.Add(1)
+	}
+	// Of the goroutines finding the tick passed, only the one which moves
+	// resetAt on starts the count again.
+	if !c.resetAt.CompareAndSwap(resetAt, now+tick) {
+		return c.n.Add(1)
+	}
+	c.n.Store(1)
+	return 1
+}
+
+// The FNV-1a hash of a level and message, computed without allocating.
+func sampleKey(level slog.Level, msg string) uint32 {
+	const prime = 16777619
+	h := uint32(2166136261)
+	h = (h ^ uint32(level)) * prime
+	for i := 0; i < len(msg); i++ {
+		h = (h ^ uint32(msg[i])) * prime
+	}
+	return h
+}
+
+// A logrus formatter which gives logrus nothing to write for the entries the
+// sampler drops. Hooks have already fired for them.
+type samplingFormatter struct {
+	log.Formatter
+	sampler *sampler
+}
+
+func (sf *samplingFormatter) Format(entry *log.Entry) ([]byte, error) {
+	if !sf.sampler.keep(logrusToSlogLevel(entry.Level), entry.Message) {
+		return nil, nil
+	}
+	return sf.Formatter.Format(entry)
+}
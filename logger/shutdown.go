@@ -0,0 +1,134 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// The outputs opened by the setup functions, to be closed by Shutdown.
+var opened struct {
+	mu      sync.Mutex
+	closers []io.Closer
+	// Those opened by the last setup of each backend, which are closed when
+	// it is set up again.
+	bySetup map[Backend][]io.Closer
+}
+
+// Serializes the setup functions, so that the outputs each opens are known.
+var setupMu sync.Mutex
+
+// Start a setup, returning the mark to pass to endSetup.
+func beginSetup() int {
+	setupMu.Lock()
+	opened.mu.Lock()
+	defer opened.mu.Unlock()
+	return len(opened.closers)
+}
+
+// Finish a setup of the given backend, begun with beginSetup, which returned
+// the given error. The outputs it opened replace those of the backend's
+// previous setup, which are closed; or, if it failed, they are closed
+// themselves.
+func endSetup(backend Backend, mark int, err error) {
+	defer setupMu.Unlock()
+	opened.mu.Lock()
+	// Shutdown may have run in the meantime.
+	mark = min(mark, len(opened.closers))
+	added := append([]io.Closer(nil), opened.closers[mark:]...)
+	stale := added
+	if err != nil {
+		opened.closers = opened.closers[:mark]
+	} else {
+		if opened.bySetup == nil {
+			opened.bySetup = make(map[Backend][]io.Closer)
+		}
+		stale = opened.bySetup[backend]
+		opened.bySetup[backend] = added
+		opened.closers = slices.DeleteFunc(opened.closers, func(c io.Closer) bool {
+			return slices.Contains(stale, c)
+		})
+	}
+	opened.mu.Unlock()
+	// In the reverse of the order opened, as for Shutdown.
+	for i := len(stale) - 1; i >= 0; i-- {
+		stale[i].Close()
+	}
+}
+
+// Keep track of an output opened by a setup function.
+func track(c io.Closer) {
+	opened.mu.Lock()
+	defer opened.mu.Unlock()
+	opened.closers = append(opened.closers, c)
+}
+
+// Flush writes out anything the outputs opened by the setup functions are
+// holding on to (see BufferSize), returning once it has been written, along
+// with any errors from writing it. Logging is otherwise synchronous (except
+// for the logging of file rotations, which Flush also waits for), so after
+// Flush returns, everything logged before the call is in the outputs: tests
+// can call it to check the output of a buffered logger without waiting for
+// FlushInterval.
+func Flush() error {
+	waitForRotations()
+	opened.mu.Lock()
+	defer opened.mu.Unlock()
+	var errs []error
+	for _, c := range opened.closers {
+		if f, ok := c.(interface{ Flush() error }); ok {
+			if err := f.Flush(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown closes the outputs (log files) opened by the setup functions,
+// having written out any buffered output (see BufferSize), for use when a
+// program exits; anything logged afterwards to those outputs is lost. If ctx
+// is done before all of the outputs are closed (e.g. writing to one is stuck),
+// Shutdown gives up waiting and returns ctx.Err() (wrapped) along with the
+// number of records not delivered: those still buffered, or being written
+// out, by the outputs not yet closed. Otherwise, it returns any errors from
+// closing the outputs.
+func Shutdown(ctx context.Context) error {
+	opened.mu.Lock()
+	closers := opened.closers
+	opened.closers = nil
+	opened.bySetup = nil
+	opened.mu.Unlock()
+
+	remaining := int64(len(closers))
+	done := make(chan error, 1)
+	go func() {
+		waitForRotations()
+		var errs []error
+		// In the reverse of the order opened, so that buffers are
+		// written out before the files they write to are closed.
+		for i := len(closers) - 1; i >= 0; i-- {
+			if err := closers[i].Close(); err != nil {
+				errs = append(errs, err)
+			}
+			atomic.AddInt64(&remaining, -1)
+		}
+		done <- errors.Join(errs...)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		var undelivered int64
+		for _, c := range closers[:atomic.LoadInt64(&remaining)] {
+			if bw, ok := c.(*bufferedWriter); ok {
+				undelivered += bw.undelivered()
+			}
+		}
+		return fmt.Errorf("%w: %d records not delivered", ctx.Err(), undelivered)
+	}
+}
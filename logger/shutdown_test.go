@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// A writer whose writes wait until it is released, as for a dead sink.
+type stuckWriter struct {
+	release chan struct{}
+}
+
+func (sw *stuckWriter) Write(p []byte) (int, error) {
+	<-sw.release
+	return len(p), nil
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	sw := &stuckWriter{release: make(chan struct{})}
+	defer close(sw.release)
+	opts := Default()
+	opts.Writer = sw
+	opts.BufferSize = 4096
+	opts.FlushInterval = time.Hour
+	defer slog.SetDefault(slog.Default())
+	logger, err := SetupSlog(opts)
+	if err != nil {
+		t.Fatalf("SetupSlog: %v", err)
+	}
+	logger.Info("one")
+	logger.Info("two")
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown error = %v, want a timeout", err)
+	}
+	// "Logging initialized." and the two above.
+	if !strings.Contains(err.Error(), "3 records not delivered") {
+		t.Errorf("Shutdown error = %v, want 3 records not delivered", err)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	var sb strings.Builder
+	opts := Default()
+	opts.Writer = &sb
+	opts.BufferSize = 4096
+	opts.FlushInterval = time.Hour
+	defer slog.SetDefault(slog.Default())
+	logger, err := SetupSlog(opts)
+	if err != nil {
+		t.Fatalf("SetupSlog: %v", err)
+	}
+	logger.Info("delivered")
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if !strings.Contains(sb.String(), "delivered") {
+		t.Errorf("buffered record not written out: %q", sb.String())
+	}
+}
+
+// The number of outputs which Shutdown would close.
+func trackedOutputs() int {
+	opened.mu.Lock()
+	defer opened.mu.Unlock()
+	return len(opened.closers)
+}
+
+func TestSetupAgainClosesOutputs(t *testing.T) {
+	for _, backend := range []Backend{Slog, LogRUs} {
+		t.Run(backend.String(), func(t *testing.T) {
+			defer slog.SetDefault(slog.Default())
+			defer Shutdown(context.Background())
+			dir := t.TempDir()
+			setup := func(name string) *ZyLogOptions {
+				t.Helper()
+				opts := Default()
+				opts.Logger = backend
+				opts.Output = "filesystem"
+				opts.File = filepath.Join(dir, name)
+				opts.BufferSize = 4096
+				opts.FlushInterval = time.Hour
+				var err error
+				if backend == Slog {
+					_, err = SetupSlog(opts)
+				} else {
+					err = SetupLogRUs(opts)
+				}
+				if err != nil {
+					t.Fatalf("setup: %v", err)
+				}
+				return opts
+			}
+			setup("first.log")
+			tracked := trackedOutputs()
+			opened.mu.Lock()
+			first := append([]io.Closer(nil), opened.bySetup[backend]...)
+			opened.mu.Unlock()
+			for i := 0; i < 3; i++ {
+				setup("second.log")
+			}
+			if n := trackedOutputs(); n != tracked {
+				t.Errorf("%d outputs tracked after setting up again, want %d", n, tracked)
+			}
+			// The first file and its buffer were written out and closed.
+			data, err := os.ReadFile(filepath.Join(dir, "first.log"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), "Logging initialized.") {
+				t.Errorf("first log file not written out: %q", data)
+			}
+			for _, c := range first {
+				if rf, ok := c.(*rotatingFile); ok {
+					if _, err := rf.file.Write([]byte("x")); !errors.Is(err, os.ErrClosed) {
+						t.Errorf("first log file still open: %v", err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFailedSetupClosesOutputs(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	defer Shutdown(context.Background())
+	tracked := trackedOutputs()
+	dir := t.TempDir()
+	opts := Default()
+	opts.Output = "filesystem"
+	opts.File = filepath.Join(dir, "app.log")
+	opts.BufferSize = 4096
+	// The pid file is written after the outputs are opened.
+	opts.PidFile = filepath.Join(dir, "missing", "app.pid")
+	if _, err := SetupSlog(opts); err == nil {
+		t.Fatal("SetupSlog succeeded without a pid file")
+	}
+	if n := trackedOutputs(); n != tracked {
+		t.Errorf("%d outputs tracked after a failed setup, want %d", n, tracked)
+	}
+}
@@ -0,0 +1,624 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/geomyidia/zylog/internal/paint"
+)
+
+// Custom slog levels, matching the extra levels offered by logrus.
+const (
+	LevelTrace = slog.LevelDebug - 4
+	LevelFatal = slog.LevelError + 4
+	LevelPanic = slog.LevelError + 8
+)
+
+// SLogHandler is an slog.Handler which renders records in the same form as
+// the zylog TextFormatter does for logrus.
+type SLogHandler struct {
+	opts    *ZyLogOptions
+	level   *slog.LevelVar
+	painter paint.Painter
+	colours *Colours
+	writer  io.Writer
+	// Held while writing, so that lines from different goroutines never
+	// interleave, and writers which aren't safe for concurrent use can be
+	// used; it is shared by the handlers derived with WithAttrs and so on.
+	mu *sync.Mutex
+	// The number of the last record handled, for the IncludeSequence
+	// option; like mu, it is shared by the derived handlers.
+	seq *atomic.Uint64
+	// The counts for the Sampling option, shared like seq; nil without it.
+	sampler *sampler
+	// The token buckets for the RateLimits option, shared like seq; nil
+	// without it.
+	limiter *rateLimiter
+	// The keys whose values are redacted; nil if there are none.
+	redactor *redactor
+	// Where records at errLevel and above go, if not to writer, and how
+	// they are painted there.
+	errWriter  io.Writer
+	errLevel   slog.Level
+	errPainter paint.Painter
+	// The lowest level of the records which get a stack trace, if any do.
+	stackLevel slog.Level
+	stacks     bool
+	// How times are formatted.
+	times timeFormat
+	// The minimum levels of particular packages, if any are given.
+	pkgLevels *packageLevels
+	// The name given with WithName, if any.
+	name string
+	// The attributes added to every record (see defaultAttrs).
+	defaults []slog.Attr
+	// Whether records are rendered as JSON objects rather than text.
+	json bool
+	// Set when records are rendered as by the log/slog TextHandler.
+	slogText *slogTextRenderer
+	profile  *profileLabels
+	attrs    []groupedAttr
+	groups   []string
+}
+
+// An attribute added with WithAttrs, along with the groups that were open
+// when it was added.
+type groupedAttr struct {
+	groups []string
+	attr   slog.Attr
+}
+
+// SetupSlog configures an slog logger using the given options, installs it as
+// the slog default, and returns it. Bad options result in the same errors as
+// for SetupLogRUs. If the RouteLogRUs option is set, the logrus standard
+// logger is also set up to send its entries to the same handler.
+//
+// If the Destinations option is set, each record is sent to every destination
+// in turn, in its own format.
+//
+// Outputs opened by an earlier SetupSlog (log files, syslog connections, and
+// buffers) are closed once the new logger is installed.
+func SetupSlog(opts *ZyLogOptions) (_ *slog.Logger, err error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	foreign := foreignDefault()
+	if foreign != "" && opts.RefuseToReplaceForeignDefault {
+		return nil, fmt.Errorf("%w: %s", ErrForeignDefault, foreign)
+	}
+	mark := beginSetup()
+	defer func() { endSetup(Slog, mark, err) }()
+	var handler slog.Handler
+	if len(opts.Destinations) > 0 {
+		handler, err = newFanoutHandler(opts)
+	} else {
+		handler, err = newOutputHandler(opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := writePidFile(opts); err != nil {
+		return nil, err
+	}
+	level, _ := opts.ParsedLevel()
+	setupLevel.Set(level.Slog())
+	setExitFunc(opts)
+	ResetEpoch()
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+	setRotationLogger(func(old, new, trigger string) {
+		logger.Info("Log file rotated.", "old_file", old, "new_file", new, "trigger", trigger)
+	})
+	if opts.RouteLogRUs {
+		routeLogRUs(handler, opts)
+	}
+	if foreign != "" {
+		foreignDefaultWarning.Do(func() {
+			logger.Warn("Replaced a foreign slog default handler", "handler", foreign)
+		})
+	}
+	logger.Log(context.Background(), opts.InitLevel, "Logging initialized.")
+	return logger, nil
+}
+
+// Create a handler for the output(s) given by the options.
+func newOutputHandler(opts *ZyLogOptions) (*SLogHandler, error) {
+	output, err := outputWriter(opts)
+	if err != nil {
+		return nil, err
+	}
+	errOutput, err := errorOutputWriter(opts)
+	if err != nil {
+		return nil, err
+	}
+	format, err := fileFormat(opts)
+	if err != nil {
+		return nil, err
+	}
+	handler, err := NewSLogHandler(output, opts)
+	if err != nil {
+		return nil, err
+	}
+	handler.level = &setupLevel
+	handler.writer = buffered(opts, output)
+	handler.setErrorOutput(errOutput)
+	handler.json = format != FileFormatText
+	if opts.ProfileLabels {
+		handler.profile = newProfileLabels(opts, format)
+	}
+	return handler, nil
+}
+
+// Send the records at the error level and above to w, if it isn't nil,
+// coloured as ResolveColour decides for w.
+func (h *SLogHandler) setErrorOutput(w io.Writer) {
+	if w == nil {
+		return
+	}
+	h.errWriter = buffered(h.opts, w)
+	if h.slogText == nil {
+		h.errPainter = paint.New(ResolveColour(h.opts, w))
+	}
+}
+
+// MustSetupSlog is like SetupSlog, but panics if the logger can't be set up.
+func MustSetupSlog(opts *ZyLogOptions) *slog.Logger {
+	logger, err := SetupSlog(opts)
+	if err != nil {
+		panic(err.Error())
+	}
+	return logger
+}
+
+// NewSLogHandler creates a handler writing to w. The Output, Outputs, and
+// ErrorOutput options are ignored; all other options are honoured.
+func NewSLogHandler(w io.Writer, opts *ZyLogOptions) (*SLogHandler, error) {
+	level, err := parseSlogLevel(string(opts.Level))
+	if err != nil {
+		return nil, err
+	}
+	h := &SLogHandler{
+		opts:     opts,
+		level:    new(slog.LevelVar),
+		painter:  paint.New(ResolveColour(opts, w)),
+		colours:  coloursFor(opts),
+		writer:   w,
+		mu:       new(sync.Mutex),
+		seq:      new(atomic.Uint64),
+		errLevel: errorLevel(opts),
+		times:    newTimeFormat(opts.TimestampFormat, opts.CustomTimestampLayout, timeLocation(opts), opts.ElapsedPrecision),
+	}
+	h.errPainter = h.painter
+	h.level.Set(level)
+	h.stackLevel, h.stacks = stackLevel(opts)
+	h.pkgLevels = newPackageLevels(opts)
+	h.sampler = newSampler(opts)
+	h.limiter = newRateLimiter(opts)
+	h.redactor = newRedactor(opts)
+	h.defaults = defaultAttrs(opts)
+	if opts.Format == FormatSlogText {
+		h.painter = paint.New(false)
+		h.errPainter = h.painter
+		h.slogText = newSlogTextRenderer(opts)
+	}
+	if opts.ProfileLabels {
+		h.profile = newProfileLabels(opts, FileFormatText)
+	}
+	return h, nil
+}
+
+// Enabled reports whether the handler emits records at the given level. With
+// the PackageLevels option, the caller isn't known until the record is
+// handled, so records at the lowest of the package levels are enabled, and
+// are filtered by Handle.
+func (h *SLogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if h.pkgLevels != nil && level >= h.pkgLevels.min {
+		return true
+	}
+	return level >= h.level.Level()
+}
+
+// Handle formats the record and writes it as a single line. The line has the
+// same form as that of the logrus TextFormatter:
+//
+//	YYYY-mm-DDTHH:MM:SS-TZ:00 LEVEL [pkg.Func:LINENUM] ▶ logged message || key={value}, ...
+//
+// If the ReplaceAttr option is set, it is called for the time, level, source
+// and message pseudo-attributes (with the standard slog keys) as well as for
+// every attribute; an attribute whose key comes back empty is omitted.
+//
+// If the context carries a request ID (see WithRequestID and
+// EnsureRequestID), it is logged as the first attribute, under RequestIDKey.
+//
+// If the ProfileLabels option is set, rendering and writing are done under
+// pprof labels (see ProfileLabels) and their cost is added to that reported
+// by CPUCost.
+//
+// If the Sampling or RateLimits options are set, records they drop are
+// neither written nor counted; with RateLimitSummary, the number of records
+// dropped by the rate limit is noted before the next one at the same level.
+//
+// If the Counter option is set, each record handled is counted by level.
+//
+// If the IncludeSequence option is set, records are numbered from 1, in the
+// order they're handled, under SequenceKey; the handlers derived from this one
+// share its numbering.
+//
+// The values asked for by the ContextAttrs option which the context carries
+// are added to the record.
+//
+// If the SpanContext option is set, the IDs of the span active in the
+// context are added to the record, under TraceIDKey and SpanIDKey.
+//
+// If the StackTraceLevel option is set, records at that level and above get a
+// stack trace, as a "stack" attribute (see StackKey).
+//
+// If the SummaryWriter option is set, a summary line is also written to it
+// for records at WARNING and above (see writeSummary).
+//
+// Handle is safe for concurrent use: each line is written with a single
+// Write, under a lock shared by the handler and those derived from it, so
+// lines are never interleaved, even on writers which aren't themselves safe
+// for concurrent use.
+func (h *SLogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.opts.CallerSkip > 0 && r.PC != 0 {
+		r.PC = skipCaller(r.PC, h.opts.CallerSkip)
+	}
+	if h.pkgLevels != nil {
+		min := h.level.Level()
+		if level, ok := h.pkgLevels.forRecord(h.name, r.PC); ok {
+			min = level
+		}
+		if r.Level < min {
+			return nil
+		}
+	}
+	if h.sampler != nil && !h.sampler.keep(r.Level, r.Message) {
+		return nil
+	}
+	if h.limiter != nil {
+		ok, dropped := h.limiter.allow(r.Level)
+		if !ok {
+			return nil
+		}
+		if dropped > 0 && h.opts.RateLimitSummary {
+			if err := h.write(r.Level, h.render(ctx, suppressedRecord(r, dropped))); err != nil {
+				return err
+			}
+		}
+	}
+	countLevel(h.opts, r.Level)
+	if h.opts.IncludeSequence {
+		r = r.Clone()
+		r.AddAttrs(slog.Uint64(SequenceKey, h.seq.Add(1)))
+	}
+	if h.stacks && r.Level >= h.stackLevel {
+		r = r.Clone()
+		r.AddAttrs(slog.String(StackKey, captureStack(h.opts)))
+	}
+	if h.opts.IncludeGoroutineID {
+		r = r.Clone()
+		r.AddAttrs(slog.Uint64(GoroutineKey, goroutineID()))
+	}
+	if attrs := contextAttrs(ctx, h.opts.ContextAttrs); len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+	if h.opts.SpanContext != nil && ctx != nil {
+		if traceID, spanID, ok := h.opts.SpanContext(ctx); ok {
+			r = r.Clone()
+			r.AddAttrs(slog.String(TraceIDKey, traceID), slog.String(SpanIDKey, spanID))
+		}
+	}
+	var err error
+	if h.opts.ProfileLabels {
+		err = h.profiledHandle(ctx, r)
+	} else {
+		err = h.write(r.Level, h.render(ctx, r))
+	}
+	if h.opts.SummaryWriter != nil && r.Level >= slog.LevelWarn {
+		if serr := h.writeSummary(ctx, r); err == nil {
+			err = serr
+		}
+	}
+	return err
+}
+
+// Render the record as a line of text, including the trailing newline.
+func (h *SLogHandler) render(ctx context.Context, r slog.Record) string {
+	if h.errWriter != nil && r.Level >= h.errLevel && h.errPainter != h.painter {
+		// The record goes to the error output, which is painted
+		// differently.
+		h2 := *h
+		h2.painter = h.errPainter
+		return h2.render(ctx, r)
+	}
+	if h.json {
+		return h.renderJSON(ctx, r)
+	}
+	if h.slogText != nil {
+		if h.name != "" {
+			r = r.Clone()
+			r.AddAttrs(slog.String(NameKey, h.name))
+		}
+		return h.slogText.render(ctx, r)
+	}
+	var b strings.Builder
+
+	if !r.Time.IsZero() {
+		if a, ok := h.replace(nil, slog.Time(slog.TimeKey, r.Time)); ok {
+			b.WriteString(h.colours.Time.paint(h.painter, h.times.pad(formatTimeValue(a.Value, h.times))))
+		}
+	}
+	if a, ok := h.replace(nil, slog.Any(slog.LevelKey, r.Level)); ok {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		name := formatLevelValue(a.Value)
+		b.WriteString(colorLevel(h.painter, h.colours, name))
+		b.WriteString(levelPadding(name, h.opts.PadLevels))
+	}
+	if h.name != "" {
+		if a, ok := h.replace(nil, slog.String(NameKey, h.name)); ok {
+			b.WriteString(" [" + h.colours.Name.paint(h.painter, a.Value.String()) + "]")
+		}
+	}
+	if h.opts.ReportCaller && r.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{r.PC})
+		f, _ := frames.Next()
+		src := &slog.Source{Function: f.Function, File: f.File, Line: f.Line}
+		if a, ok := h.replace(nil, slog.Any(slog.SourceKey, src)); ok {
+			b.WriteString(formatSourceValue(h.painter, h.colours, h.opts.CallerFormat, h.opts.CallerFullPath, a.Value))
+		}
+	}
+	if r.Message != "" {
+		if a, ok := h.replace(nil, slog.String(slog.MessageKey, r.Message)); ok {
+			start := b.String()
+			b.WriteString(h.colours.Arrow.paint(h.painter, " ▶ "))
+			b.WriteString(formatMessage(h.painter, h.colours, start, a.Value.String(), h.opts.Multiline))
+		}
+	}
+
+	attrs := &attrList{visual: h.opts.GroupVisual}
+	if h.opts.DefaultAttrsFirst {
+		for _, a := range h.defaults {
+			h.appendAttr(attrs, nil, a)
+		}
+	}
+	if id, ok := RequestID(ctx); ok {
+		h.appendAttr(attrs, nil, slog.String(RequestIDKey, id))
+	}
+	for _, ga := range h.attrs {
+		h.appendAttr(attrs, ga.groups, ga.attr)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		h.appendAttr(attrs, h.groups, a)
+		return true
+	})
+	if !h.opts.DefaultAttrsFirst {
+		for _, a := range h.defaults {
+			h.appendAttr(attrs, nil, a)
+		}
+	}
+	if len(attrs.items) > 0 {
+		b.WriteString(" || ")
+		b.WriteString(attrs.String())
+	}
+
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// Write a rendered line to the writer for the record's level.
+func (h *SLogHandler) write(level slog.Level, line string) error {
+	w := h.writer
+	if h.errWriter != nil && level >= h.errLevel {
+		w = h.errWriter
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := writeLevel(w, level, []byte(line))
+	return err
+}
+
+// WithAttrs returns a handler which includes the given attributes in every
+// record it handles.
+func (h *SLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.attrs = make([]groupedAttr, len(h.attrs), len(h.attrs)+len(attrs))
+	copy(h2.attrs, h.attrs)
+	for _, a := range attrs {
+		h2.attrs = append(h2.attrs, groupedAttr{groups: h.groups, attr: a})
+	}
+	if h.slogText != nil {
+		h2.slogText = h.slogText.withAttrs(attrs)
+	}
+	return &h2
+}
+
+// WithGroup returns a handler which qualifies the keys of all subsequent
+// attributes with the given group name.
+func (h *SLogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.groups = make([]string, len(h.groups), len(h.groups)+1)
+	copy(h2.groups, h.groups)
+	h2.groups = append(h2.groups, name)
+	if h.slogText != nil {
+		h2.slogText = h.slogText.withGroup(name)
+	}
+	return &h2
+}
+
+// Add an attribute to the list, qualified by any open groups. Group values are
+// added member by member, with the group's key qualifying those of its
+// members; empty groups are skipped.
+func (h *SLogHandler) appendAttr(attrs *attrList, groups []string, a slog.Attr) {
+	// Resolving would lose the colour of a ColouredValue.
+	cv, coloured := a.Value.Any().(ColouredValue)
+	a.Value = a.Value.Resolve()
+	if h.opts.ExpandStructs && a.Value.Kind() == slog.KindAny {
+		a.Value = expandValue(a.Value.Any(), 0)
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		members := a.Value.Group()
+		if len(members) == 0 {
+			return
+		}
+		if a.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], a.Key)
+		}
+		for _, m := range members {
+			h.appendAttr(attrs, groups, m)
+		}
+		return
+	}
+	a, q, ok := h.replaceQuantity(groups, a)
+	if !ok {
+		return
+	}
+	var value string
+	switch {
+	case h.opts.RawValues:
+		value = a.Value.String()
+	case q != nil:
+		value = q.scaled(h.opts.SIUnits)
+	default:
+		value = formatSlogValue(a.Value, h.times)
+	}
+	if !h.opts.NoEscape {
+		value = escapeValue(value)
+	}
+	err, isError := a.Value.Any().(error)
+	if a.Value.Kind() != slog.KindAny {
+		isError = false
+	}
+	switch {
+	case coloured:
+		value = cv.Colour.paint(h.painter, value)
+	case isError:
+		value = h.colours.Error.paint(h.painter, value)
+	}
+	attrs.add(groups, a.Key, value)
+	if isError && h.opts.VerboseErrors {
+		for i, cause := range errorCauses(err) {
+			h.appendAttr(attrs, groups, slog.String(causeKey(a.Key, i), cause.Error()))
+		}
+	}
+}
+
+// Apply the ReplaceAttr option, if any, reporting whether the attribute
+// should still be output.
+func (h *SLogHandler) replace(groups []string, a slog.Attr) (slog.Attr, bool) {
+	if h.opts.ReplaceAttr != nil {
+		a = h.opts.ReplaceAttr(groups, a)
+	}
+	return a, a.Key != ""
+}
+
+// Convert a level string to an slog level, supporting the same names as
+// logrus.
+func parseSlogLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "trace":
+		return LevelTrace, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	case "fatal":
+		return LevelFatal, nil
+	case "panic":
+		return LevelPanic, nil
+	}
+	if cl, ok := lookupCustomLevel(level); ok {
+		return cl.level, nil
+	}
+	return 0, ErrLogLevel
+}
+
+// Convert an slog level to the upper-case names used by ColorLevel.
+func slogLevelToString(level slog.Level) string {
+	if cl, ok := customLevelAt(level); ok {
+		return cl.name
+	}
+	switch {
+	case level < slog.LevelDebug:
+		return "TRACE"
+	case level < slog.LevelInfo:
+		return "DEBUG"
+	case level < slog.LevelWarn:
+		return "INFO"
+	case level < slog.LevelError:
+		return "WARNING"
+	case level < LevelFatal:
+		return "ERROR"
+	case level < LevelPanic:
+		return "FATAL"
+	}
+	return "PANIC"
+}
+
+// Render an attribute value according to its kind, in the same way as the
+// log/slog text handler (but without quoting, since values are braced).
+func formatSlogValue(v slog.Value, tf timeFormat) string {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindInt64:
+		return strconv.FormatInt(v.Int64(), 10)
+	case slog.KindUint64:
+		return strconv.FormatUint(v.Uint64(), 10)
+	case slog.KindFloat64:
+		return strconv.FormatFloat(v.Float64(), 'g', -1, 64)
+	case slog.KindBool:
+		return strconv.FormatBool(v.Bool())
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindTime:
+		return formatTimeValue(v, tf)
+	case slog.KindLogValuer:
+		return formatSlogValue(v.Resolve(), tf)
+	}
+	if err, ok := v.Any().(error); ok {
+		return err.Error()
+	}
+	return formatAnyValue(v.Any())
+}
+
+func formatTimeValue(v slog.Value, tf timeFormat) string {
+	if v.Kind() == slog.KindTime {
+		return tf.format(v.Time())
+	}
+	return v.String()
+}
+
+func formatLevelValue(v slog.Value) string {
+	if l, ok := v.Any().(slog.Level); ok {
+		return slogLevelToString(l)
+	}
+	return strings.ToUpper(v.String())
+}
+
+func formatSourceValue(p paint.Painter, c *Colours, format string, fullPath bool, v slog.Value) string {
+	if src, ok := v.Any().(*slog.Source); ok {
+		return formatCaller(p, c, format, fullPath, src.Function, src.File, src.Line)
+	}
+	return fmt.Sprintf(" [%s]", c.Function.paint(p, v.String()))
+}
@@ -0,0 +1,149 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sort"
+)
+
+// Render the record as a JSON object on a single line. The time, level,
+// source and message come first, under the standard slog keys, followed by
+// the attributes in key order; groups become nested objects.
+func (h *SLogHandler) renderJSON(ctx context.Context, r slog.Record) string {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	field := func(key string, value any) {
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		b.Write(marshalJSON(key))
+		b.WriteByte(':')
+		b.Write(marshalJSON(value))
+	}
+
+	if !r.Time.IsZero() {
+		if a, ok := h.replace(nil, slog.Time(slog.TimeKey, r.Time)); ok {
+			field(a.Key, formatTimeValue(a.Value, h.times))
+		}
+	}
+	if a, ok := h.replace(nil, slog.Any(slog.LevelKey, r.Level)); ok {
+		field(a.Key, formatLevelValue(a.Value))
+	}
+	if h.name != "" {
+		if a, ok := h.replace(nil, slog.String(NameKey, h.name)); ok {
+			field(a.Key, a.Value.String())
+		}
+	}
+	if h.opts.ReportCaller && r.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{r.PC})
+		f, _ := frames.Next()
+		src := &slog.Source{Function: f.Function, File: f.File, Line: f.Line}
+		if a, ok := h.replace(nil, slog.Any(slog.SourceKey, src)); ok {
+			field(a.Key, jsonValue(a.Value, h.times))
+		}
+	}
+	if a, ok := h.replace(nil, slog.String(slog.MessageKey, r.Message)); ok {
+		field(a.Key, a.Value.String())
+	}
+
+	attrs := map[string]any{}
+	if id, ok := RequestID(ctx); ok {
+		h.addJSONAttr(attrs, nil, slog.String(RequestIDKey, id))
+	}
+	// Keys are sorted, so the defaults come first only to be overridden.
+	for _, a := range h.defaults {
+		h.addJSONAttr(attrs, nil, a)
+	}
+	for _, ga := range h.attrs {
+		h.addJSONAttr(attrs, ga.groups, ga.attr)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		h.addJSONAttr(attrs, h.groups, a)
+		return true
+	})
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		field(k, attrs[k])
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Add an attribute to a map of attributes, nesting it within a map for each
+// of the open groups.
+func (h *SLogHandler) addJSONAttr(m map[string]any, groups []string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		members := a.Value.Group()
+		if len(members) == 0 {
+			return
+		}
+		if a.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], a.Key)
+		}
+		for _, member := range members {
+			h.addJSONAttr(m, groups, member)
+		}
+		return
+	}
+	a, q, ok := h.replaceQuantity(groups, a)
+	if !ok {
+		return
+	}
+	for _, g := range groups {
+		sub, ok := m[g].(map[string]any)
+		if !ok {
+			sub = map[string]any{}
+			m[g] = sub
+		}
+		m = sub
+	}
+	m[a.Key] = jsonValue(a.Value, h.times)
+	if q != nil && q.Unit != "" {
+		m[a.Key+"_unit"] = q.Unit
+	}
+}
+
+// Convert an attribute value to one which encodes to JSON sensibly.
+func jsonValue(v slog.Value, tf timeFormat) any {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindInt64:
+		return v.Int64()
+	case slog.KindUint64:
+		return v.Uint64()
+	case slog.KindFloat64:
+		return v.Float64()
+	case slog.KindBool:
+		return v.Bool()
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindTime:
+		return formatTimeValue(v, tf)
+	case slog.KindLogValuer:
+		return jsonValue(v.Resolve(), tf)
+	}
+	if err, ok := v.Any().(error); ok {
+		return err.Error()
+	}
+	return v.Any()
+}
+
+// Encode a value as JSON, falling back to its %+v form when it can't be.
+func marshalJSON(v any) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprintf("%+v", v))
+	}
+	return data
+}
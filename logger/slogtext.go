@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// Formats for the slog handler's output.
+const (
+	FormatZylog    = "zylog"
+	FormatSlogText = "slog-text"
+)
+
+// Renders records using the log/slog TextHandler, so that the output is the
+// same as that handler's, other than the timestamp being in zylog's format.
+// The TextHandler writes to a buffer shared by all of the handlers derived
+// from the original one with WithAttrs and WithGroup, so rendering is done
+// under a lock.
+type slogTextRenderer struct {
+	handler slog.Handler
+	shared  *slogTextBuffer
+}
+
+type slogTextBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func newSlogTextRenderer(opts *ZyLogOptions) *slogTextRenderer {
+	shared := &slogTextBuffer{}
+	tf := newTimeFormat(opts.TimestampFormat, opts.CustomTimestampLayout, timeLocation(opts), opts.ElapsedPrecision)
+	redactor := newRedactor(opts)
+	var handler slog.Handler = slog.NewTextHandler(&shared.buf, &slog.HandlerOptions{
+		AddSource: opts.ReportCaller,
+		// Levels are filtered by the SLogHandler.
+		Level: slog.Level(-1 << 31),
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if !builtinKey(groups, a.Key) {
+				a = redactor.redact(groups, a)
+			}
+			if opts.ReplaceAttr != nil {
+				a = opts.ReplaceAttr(groups, a)
+			}
+			if len(groups) == 0 && a.Key == slog.TimeKey && a.Value.Kind() == slog.KindTime {
+				a.Value = slog.StringValue(formatTimeValue(a.Value, tf))
+			}
+			return a
+		},
+	})
+	// The TextHandler puts attributes added with WithAttrs first, whatever
+	// DefaultAttrsFirst says.
+	if defaults := defaultAttrs(opts); len(defaults) > 0 {
+		handler = handler.WithAttrs(defaults)
+	}
+	return &slogTextRenderer{handler: handler, shared: shared}
+}
+
+func (t *slogTextRenderer) render(ctx context.Context, r slog.Record) string {
+	if id, ok := RequestID(ctx); ok {
+		r = r.Clone()
+		r.AddAttrs(slog.String(RequestIDKey, id))
+	}
+	t.shared.mu.Lock()
+	defer t.shared.mu.Unlock()
+	t.shared.buf.Reset()
+	if err := t.handler.Handle(ctx, r); err != nil {
+		return fmt.Sprintf("!ERROR rendering record: %v\n", err)
+	}
+	return t.shared.buf.String()
+}
+
+func (t *slogTextRenderer) withAttrs(attrs []slog.Attr) *slogTextRenderer {
+	return &slogTextRenderer{handler: t.handler.WithAttrs(attrs), shared: t.shared}
+}
+
+func (t *slogTextRenderer) withGroup(name string) *slogTextRenderer {
+	return &slogTextRenderer{handler: t.handler.WithGroup(name), shared: t.shared}
+}
+
+// Whether the attribute is one of the TextHandler's own, the time, level,
+// source, or message.
+func builtinKey(groups []string, key string) bool {
+	if len(groups) > 0 {
+		return false
+	}
+	switch key {
+	case slog.TimeKey, slog.LevelKey, slog.SourceKey, slog.MessageKey:
+		return true
+	}
+	return false
+}
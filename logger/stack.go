@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The key of the attribute (or logrus field) holding a stack trace.
+const StackKey = "stack"
+
+// The number of frames in a stack trace, unless the StackDepth option says
+// otherwise.
+const defaultStackDepth = 16
+
+// The packages whose frames are left off the top of a stack trace, so that it
+// starts at the caller of the logging function.
+var internalPackages = []string{
+	"log/slog.",
+	"github.com/sirupsen/logrus.",
+	"github.com/geomyidia/zylog.",
+	"github.com/geomyidia/zylog/logger.",
+}
+
+// Capture the stack of the caller of the logging function, one frame per line
+// ("function file:line"), up to the StackDepth option's number of frames,
+// leaving out the runtime's own frames unless StackRuntimeFrames is set.
+func captureStack(opts *ZyLogOptions) string {
+	depth := opts.StackDepth
+	if depth <= 0 {
+		depth = defaultStackDepth
+	}
+	pcs := make([]uintptr, depth+32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var lines []string
+	inside := true
+	for len(lines) < depth {
+		f, more := frames.Next()
+		if inside && isInternalFrame(f.Function) {
+			if !more {
+				break
+			}
+			continue
+		}
+		inside = false
+		if opts.StackRuntimeFrames || !strings.HasPrefix(f.Function, "runtime.") {
+			lines = append(lines, fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line))
+		}
+		if !more {
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isInternalFrame(function string) bool {
+	for _, p := range internalPackages {
+		if strings.HasPrefix(function, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// The lowest level of the records which get a stack trace, and whether any do.
+func stackLevel(opts *ZyLogOptions) (slog.Level, bool) {
+	if opts.StackTraceLevel == "" {
+		return 0, false
+	}
+	level, err := parseSlogLevel(opts.StackTraceLevel)
+	if err != nil {
+		return 0, false
+	}
+	return level, true
+}
+
+// A logrus hook which adds a stack trace to entries at the StackTraceLevel
+// and above.
+type stackHook struct {
+	opts   *ZyLogOptions
+	levels []log.Level
+}
+
+func (sh *stackHook) Levels() []log.Level {
+	return sh.levels
+}
+
+func (sh *stackHook) Fire(entry *log.Entry) error {
+	// The fields may be shared with the entry which this one was made from,
+	// so they are copied rather than added to.
+	data := make(log.Fields, len(entry.Data)+1)
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	data[StackKey] = captureStack(sh.opts)
+	entry.Data = data
+	return nil
+}
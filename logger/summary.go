@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/geomyidia/zylog/internal/paint"
+)
+
+// Write a summary of the record to the SummaryWriter: its level and message,
+// followed by the values of those of the SummaryKeys attributes which it has,
+// in the order of SummaryKeys. The summary is never coloured, whatever the
+// main output is.
+func (h *SLogHandler) writeSummary(ctx context.Context, r slog.Record) error {
+	plain := *h
+	plain.painter = paint.New(false)
+	attrs := &attrList{values: map[string]string{}}
+	if id, ok := RequestID(ctx); ok {
+		plain.appendAttr(attrs, nil, slog.String(RequestIDKey, id))
+	}
+	for _, ga := range h.attrs {
+		plain.appendAttr(attrs, ga.groups, ga.attr)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		plain.appendAttr(attrs, h.groups, a)
+		return true
+	})
+
+	var b strings.Builder
+	b.WriteString(slogLevelToString(r.Level))
+	if r.Message != "" {
+		b.WriteByte(' ')
+		b.WriteString(r.Message)
+	}
+	var pairs []string
+	for _, key := range h.opts.SummaryKeys {
+		if value, ok := attrs.values[key]; ok {
+			pairs = append(pairs, key+"="+value)
+		}
+	}
+	if len(pairs) > 0 {
+		b.WriteString(" [")
+		b.WriteString(strings.Join(pairs, " "))
+		b.WriteByte(']')
+	}
+	b.WriteByte('\n')
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.opts.SummaryWriter, b.String())
+	return err
+}
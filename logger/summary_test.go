@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestSummaryUncoloured(t *testing.T) {
+	clearColourEnv(t)
+	var out, summary bytes.Buffer
+	opts := Default()
+	opts.ForceColor = true
+	opts.SummaryWriter = &summary
+	opts.SummaryKeys = []string{"error", "status"}
+	h, err := NewSLogHandler(&out, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slog.New(h).Error("boom", "error", errors.New("bad"),
+		"status", Coloured(500, Colour{Fg: color.FgRed}))
+	if want := "ERROR boom [error=bad status=500]\n"; summary.String() != want {
+		t.Errorf("summary = %q, want %q", summary.String(), want)
+	}
+	// The main line is still coloured.
+	if !strings.Contains(out.String(), "\x1b[31mbad\x1b[0m") || !strings.Contains(out.String(), "\x1b[31m500\x1b[0m") {
+		t.Errorf("output = %q, want its values coloured", out.String())
+	}
+}
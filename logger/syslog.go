@@ -0,0 +1,54 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"log/slog"
+	"log/syslog"
+)
+
+// A writer sending each line to syslog, at the severity matching the level
+// of the record it is for (LOG_INFO when that isn't known). Colour codes are
+// stripped, and the trailing newline is left to syslog.
+type syslogWriter struct {
+	w *syslog.Writer
+}
+
+// Connect to the syslog daemon given by the SyslogNetwork and SyslogAddr
+// options (the local one when they're empty).
+func openSyslog(opts *ZyLogOptions) (*syslogWriter, error) {
+	w, err := syslog.Dial(opts.SyslogNetwork, opts.SyslogAddr, syslog.LOG_INFO|syslog.LOG_USER, opts.SyslogTag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogWriter{w: w}, nil
+}
+
+func (sw *syslogWriter) Write(p []byte) (int, error) {
+	return sw.WriteLevel(slog.LevelInfo, p)
+}
+
+func (sw *syslogWriter) WriteLevel(level slog.Level, p []byte) (int, error) {
+	msg := string(ansiEscape.ReplaceAll(p, nil))
+	var err error
+	switch {
+	case level >= LevelFatal:
+		err = sw.w.Crit(msg)
+	case level >= slog.LevelError:
+		err = sw.w.Err(msg)
+	case level >= slog.LevelWarn:
+		err = sw.w.Warning(msg)
+	case level >= slog.LevelInfo:
+		err = sw.w.Info(msg)
+	default:
+		err = sw.w.Debug(msg)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (sw *syslogWriter) Close() error {
+	return sw.w.Close()
+}
@@ -0,0 +1,27 @@
+//go:build windows || plan9
+
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// There's no syslog here; see syslog.go.
+type syslogWriter struct{}
+
+func openSyslog(opts *ZyLogOptions) (*syslogWriter, error) {
+	return nil, fmt.Errorf("%w: syslog on this platform", ErrNotImplemented)
+}
+
+func (sw *syslogWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (sw *syslogWriter) WriteLevel(level slog.Level, p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (sw *syslogWriter) Close() error {
+	return nil
+}
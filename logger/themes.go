@@ -0,0 +1,181 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/fatih/color"
+)
+
+// The colour presets, by name.
+var colourPresets = map[string]func() *Colours{
+	"default":        DefaultColours,
+	"dark":           DarkColours,
+	"light":          LightColours,
+	"monokai":        MonokaiColours,
+	"solarized":      SolarizedColours,
+	"solarized-dark": SolarizedDarkColours,
+	"grayscale":      GrayscaleColours,
+	"monochrome":     MonochromeColours,
+}
+
+// ColourPresets returns the names of the colour presets, which PresetColours
+// accepts.
+func ColourPresets() []string {
+	names := make([]string, 0, len(colourPresets))
+	for name := range colourPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PresetColours returns the colour preset with the given name (see
+// ColourPresets); an unknown name results in ErrUnknownColour (wrapped).
+func PresetColours(name string) (*Colours, error) {
+	preset, ok := colourPresets[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: no preset named %q", ErrUnknownColour, name)
+	}
+	return preset(), nil
+}
+
+// DarkColours returns colours for terminals with a dark background: the
+// default colours.
+func DarkColours() *Colours {
+	return DefaultColours()
+}
+
+// LightColours returns colours for terminals with a light background, on
+// which the bright colours of the default ones are hard to read.
+func LightColours() *Colours {
+	return &Colours{
+		Time:     Colour{Fg: color.FgHiBlack},
+		Trace:    Colour{Fg: color.FgMagenta},
+		Debug:    Colour{Fg: color.FgBlue},
+		Info:     Colour{Fg: color.FgGreen},
+		Warning:  Colour{Fg: color.FgYellow, Styles: []color.Attribute{color.Bold}},
+		Error:    Colour{Fg: color.FgRed},
+		Fatal:    Colour{Fg: color.FgHiWhite, Bg: color.BgRed},
+		Panic:    Colour{Fg: color.FgHiWhite, Bg: color.BgMagenta},
+		Name:     Colour{Fg: color.FgBlue},
+		Function: Colour{Fg: color.FgMagenta},
+		Line:     Colour{Fg: color.FgBlack},
+		Arrow:    Colour{Fg: color.FgCyan},
+	}
+}
+
+// MonochromeColours returns a theme without colour, telling the levels apart
+// by the bold and faint styles alone: the levels below INFO are faint, and
+// those above it bold.
+func MonochromeColours() *Colours {
+	// Each part gets its own Styles, so that changing one doesn't change
+	// the others.
+	faint := func() Colour { return Colour{Styles: []color.Attribute{color.Faint}} }
+	bold := func() Colour { return Colour{Styles: []color.Attribute{color.Bold}} }
+	return &Colours{
+		Time:     faint(),
+		Trace:    faint(),
+		Debug:    faint(),
+		Info:     Colour{},
+		Warning:  bold(),
+		Error:    bold(),
+		Fatal:    bold(),
+		Panic:    bold(),
+		Name:     bold(),
+		Function: faint(),
+		Line:     faint(),
+		Arrow:    faint(),
+	}
+}
+
+// MonokaiColours returns colours after the Monokai editor theme. It uses
+// 24-bit colours.
+func MonokaiColours() *Colours {
+	const (
+		pink   = 0xf92672
+		green  = 0xa6e22e
+		orange = 0xfd971f
+		yellow = 0xe6db74
+		purple = 0xae81ff
+		blue   = 0x66d9ef
+		grey   = 0x75715e
+		white  = 0xf8f8f2
+	)
+	return &Colours{
+		Time:     Colour{FgRGB: hexRGB(grey)},
+		Trace:    Colour{FgRGB: hexRGB(purple)},
+		Debug:    Colour{FgRGB: hexRGB(blue)},
+		Info:     Colour{FgRGB: hexRGB(green)},
+		Warning:  Colour{FgRGB: hexRGB(orange)},
+		Error:    Colour{FgRGB: hexRGB(pink)},
+		Fatal:    Colour{FgRGB: hexRGB(white), BgRGB: hexRGB(pink)},
+		Panic:    Colour{FgRGB: hexRGB(white), BgRGB: hexRGB(purple)},
+		Name:     Colour{FgRGB: hexRGB(blue)},
+		Function: Colour{FgRGB: hexRGB(yellow)},
+		Line:     Colour{FgRGB: hexRGB(orange)},
+		Arrow:    Colour{FgRGB: hexRGB(pink)},
+	}
+}
+
+// SolarizedDarkColours returns colours after the dark Solarized palette. It
+// uses 24-bit colours.
+func SolarizedDarkColours() *Colours {
+	const (
+		base01  = 0x586e75
+		base3   = 0xfdf6e3
+		yellow  = 0xb58900
+		orange  = 0xcb4b16
+		red     = 0xdc322f
+		magenta = 0xd33682
+		violet  = 0x6c71c4
+		blue    = 0x268bd2
+		cyan    = 0x2aa198
+		green   = 0x859900
+	)
+	return &Colours{
+		Time:     Colour{FgRGB: hexRGB(base01)},
+		Trace:    Colour{FgRGB: hexRGB(violet)},
+		Debug:    Colour{FgRGB: hexRGB(cyan)},
+		Info:     Colour{FgRGB: hexRGB(green)},
+		Warning:  Colour{FgRGB: hexRGB(yellow)},
+		Error:    Colour{FgRGB: hexRGB(red)},
+		Fatal:    Colour{FgRGB: hexRGB(base3), BgRGB: hexRGB(red)},
+		Panic:    Colour{FgRGB: hexRGB(base3), BgRGB: hexRGB(magenta)},
+		Name:     Colour{FgRGB: hexRGB(violet)},
+		Function: Colour{FgRGB: hexRGB(blue)},
+		Line:     Colour{FgRGB: hexRGB(cyan)},
+		Arrow:    Colour{FgRGB: hexRGB(orange)},
+	}
+}
+
+// SolarizedColours returns colours after the Solarized palette. Its accent
+// colours are meant for both the dark and the light Solarized backgrounds,
+// so these are the same as SolarizedDarkColours. It uses 24-bit colours.
+func SolarizedColours() *Colours {
+	return SolarizedDarkColours()
+}
+
+// GrayscaleColours returns colours without hue, for terminals (or readers)
+// which don't do colour well; the more severe levels are shown in reverse.
+func GrayscaleColours() *Colours {
+	return &Colours{
+		Time:     Colour{Fg: color.FgHiBlack},
+		Trace:    Colour{Fg: color.FgHiBlack},
+		Debug:    Colour{Fg: color.FgWhite},
+		Info:     Colour{Fg: color.FgHiWhite},
+		Warning:  Colour{Fg: color.FgBlack, Bg: color.BgWhite},
+		Error:    Colour{Fg: color.FgBlack, Bg: color.BgHiWhite},
+		Fatal:    Colour{Fg: color.FgHiWhite, Bg: color.BgHiBlack},
+		Panic:    Colour{Fg: color.FgHiWhite, Bg: color.BgBlack},
+		Name:     Colour{Fg: color.FgWhite},
+		Function: Colour{Fg: color.FgWhite},
+		Line:     Colour{Fg: color.FgHiBlack},
+		Arrow:    Colour{Fg: color.FgHiBlack},
+	}
+}
+
+// A 24-bit colour given as 0xRRGGBB.
+func hexRGB(v uint32) *RGB {
+	return &RGB{uint8(v >> 16), uint8(v >> 8), uint8(v)}
+}
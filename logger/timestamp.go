@@ -0,0 +1,157 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// The timestamp formats (see ZyLogOptions.TimestampFormat).
+const (
+	TimestampStandard     = "standard"      // 2006-01-02T15:04:05Z07:00 (RFC 3339)
+	TimestampRFC3339      = "rfc3339"       // the same as standard
+	TimestampSimple       = "simple"        // 20060102.150405
+	TimestampTimeOnly     = "time-only"     // 15:04:05
+	TimestampSimpleMillis = "simple-millis" // 20060102.150405.000
+	TimestampSimpleMicros = "simple-micros" // 20060102.150405.000000
+	TimestampTimeMillis   = "time-millis"   // 15:04:05.000
+	TimestampRFC3339Milli = "rfc3339-milli" // 2006-01-02T15:04:05.000Z07:00
+	TimestampRFC3339Nano  = "rfc3339-nano"  // 2006-01-02T15:04:05.999999999Z07:00
+	TimestampUnix         = "unix"          // seconds since the epoch, e.g. 1136214245
+	TimestampUnixMillis   = "unix-millis"   // milliseconds since the epoch
+	TimestampElapsed      = "elapsed"       // time since setup (see ResetEpoch), e.g. +0.482s
+)
+
+var timestampLayouts = map[string]string{
+	"":                    time.RFC3339,
+	TimestampStandard:     time.RFC3339,
+	TimestampRFC3339:      time.RFC3339,
+	TimestampSimple:       "20060102.150405",
+	TimestampTimeOnly:     "15:04:05",
+	TimestampSimpleMillis: "20060102.150405.000",
+	TimestampSimpleMicros: "20060102.150405.000000",
+	TimestampTimeMillis:   "15:04:05.000",
+	TimestampRFC3339Milli: "2006-01-02T15:04:05.000Z07:00",
+	TimestampRFC3339Nano:  time.RFC3339Nano,
+	// Not layouts; see timeFormat.format.
+	TimestampUnix:       "",
+	TimestampUnixMillis: "",
+	TimestampElapsed:    "",
+}
+
+// The instant from which elapsed timestamps count, in nanoseconds since the
+// Unix epoch.
+var epoch atomic.Int64
+
+func init() {
+	ResetEpoch()
+}
+
+// ResetEpoch restarts the clock shown by elapsed timestamps (see
+// TimestampElapsed) from now. SetupSlog and SetupLogRUs call it, so that the
+// times count from when logging was set up.
+func ResetEpoch() {
+	epoch.Store(time.Now().UnixNano())
+}
+
+// The default number of digits after the decimal point of elapsed
+// timestamps: milliseconds.
+const defaultElapsedPrecision = 3
+
+// How times are formatted, as given by the options.
+type timeFormat struct {
+	layout string
+	// The zone in which times are shown; nil for the local one.
+	loc *time.Location
+	// For Unix times, the length of a unit: a second or a millisecond.
+	unix time.Duration
+	// For elapsed times, the number of digits after the decimal point; 0
+	// for other formats.
+	elapsed int
+}
+
+// The time format for the TimestampFormat, CustomTimestampLayout, and
+// ElapsedPrecision options, in the given zone (nil for the local one); an
+// unknown format (which Validate rejects) gives the standard one.
+func newTimeFormat(format, customLayout string, loc *time.Location, precision int) timeFormat {
+	if customLayout != "" {
+		return timeFormat{layout: customLayout, loc: loc}
+	}
+	switch format {
+	case TimestampUnix:
+		return timeFormat{unix: time.Second}
+	case TimestampUnixMillis:
+		return timeFormat{unix: time.Millisecond}
+	case TimestampElapsed:
+		if precision <= 0 || precision > 9 {
+			precision = defaultElapsedPrecision
+		}
+		return timeFormat{elapsed: precision}
+	}
+	layout, ok := timestampLayouts[format]
+	if !ok {
+		layout = time.RFC3339
+	}
+	return timeFormat{layout: layout, loc: loc}
+}
+
+// The zone in which times are shown, as given by the Location, TimeLocation,
+// and UTC options; nil for the local zone. An unknown TimeLocation (which
+// Validate rejects) is ignored.
+func timeLocation(opts *ZyLogOptions) *time.Location {
+	if opts.Location != nil {
+		return opts.Location
+	}
+	if opts.TimeLocation != "" {
+		if loc, err := time.LoadLocation(opts.TimeLocation); err == nil {
+			return loc
+		}
+	}
+	if opts.UTC {
+		return time.UTC
+	}
+	return nil
+}
+
+// Check that a custom layout has something to show: one which formats a
+// time as the empty string, or as itself, has no layout elements in it.
+func validateLayout(layout string) error {
+	sample := time.Date(2001, 2, 3, 4, 5, 6, 7, time.UTC).Format(layout)
+	if sample == "" || sample == layout {
+		return fmt.Errorf("%w: timestamp layout %q", ErrInvalidOption, layout)
+	}
+	return nil
+}
+
+// Format a time as logged, both for the line timestamp and for time values in
+// attributes and fields, so that the two are always in the same form and
+// zone.
+func (tf timeFormat) format(t time.Time) string {
+	if tf.unix != 0 {
+		return strconv.FormatInt(t.UnixNano()/int64(tf.unix), 10)
+	}
+	if tf.elapsed != 0 {
+		return formatElapsed(time.Duration(t.UnixNano()-epoch.Load()), tf.elapsed)
+	}
+	if tf.loc != nil {
+		return t.In(tf.loc).Format(tf.layout)
+	}
+	return t.Local().Format(tf.layout)
+}
+
+// Format the time since the epoch in seconds, with the given number of digits
+// after the decimal point, e.g. "+0.482s".
+func formatElapsed(d time.Duration, precision int) string {
+	return fmt.Sprintf("%+.*fs", precision, d.Seconds())
+}
+
+// Pad a formatted line timestamp to a fixed width, for elapsed times, whose
+// width otherwise grows with them: room is left for four digits of seconds,
+// so that lines stay aligned for the first few hours.
+func (tf timeFormat) pad(ts string) string {
+	if tf.elapsed == 0 {
+		return ts
+	}
+	return fmt.Sprintf("%*s", tf.elapsed+7, ts)
+}
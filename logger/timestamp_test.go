@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The time of an attribute in the timestamp tests, in another zone from the
+// record's.
+var attrTime = time.Date(2024, 1, 2, 3, 4, 5, 6000000, time.UTC)
+
+// The zone in which the timestamp tests show times.
+var testZone = time.FixedZone("IST", 5*3600+1800)
+
+var timeValueTests = []struct {
+	format     string
+	line, attr string
+}{
+	{TimestampStandard, "2024-03-01T18:00:45+05:30", "2024-01-02T08:34:05+05:30"},
+	{TimestampRFC3339, "2024-03-01T18:00:45+05:30", "2024-01-02T08:34:05+05:30"},
+	{TimestampRFC3339Milli, "2024-03-01T18:00:45.000+05:30", "2024-01-02T08:34:05.006+05:30"},
+	{TimestampSimple, "20240301.180045", "20240102.083405"},
+	{TimestampTimeMillis, "18:00:45.000", "08:34:05.006"},
+	{TimestampUnix, "1709296245", "1704164645"},
+}
+
+func TestTimeValuesSlog(t *testing.T) {
+	for _, tt := range timeValueTests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			opts := Default()
+			opts.Colored = false
+			opts.Location = testZone
+			opts.TimestampFormat = tt.format
+			h, err := NewSLogHandler(&buf, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := slog.NewRecord(goldenTime, slog.LevelInfo, "m", 0)
+			r.AddAttrs(slog.Time("at", attrTime), slog.Group("g", slog.Time("at", attrTime)))
+			if err := h.Handle(context.Background(), r); err != nil {
+				t.Fatal(err)
+			}
+			want := tt.line + " INFO ▶ m || at={" + tt.attr + "}, g.at={" + tt.attr + "}, \n"
+			if buf.String() != want {
+				t.Errorf("got  %q\nwant %q", buf.String(), want)
+			}
+
+			buf.Reset()
+			h.json = true
+			if err := h.Handle(context.Background(), r); err != nil {
+				t.Fatal(err)
+			}
+			var record map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("invalid JSON: %v\n%s", err, buf.Bytes())
+			}
+			if record["time"] != tt.line || record["at"] != tt.attr {
+				t.Errorf("JSON time %v and at %v, want %s and %s", record["time"], record["at"], tt.line, tt.attr)
+			}
+		})
+	}
+}
+
+func TestTimeValuesLogrus(t *testing.T) {
+	for _, tt := range timeValueTests {
+		t.Run(tt.format, func(t *testing.T) {
+			tf := &TextFormatter{
+				DisableColors:   true,
+				Colours:         DefaultColours(),
+				TimestampFormat: tt.format,
+				Location:        testZone,
+			}
+			entry := log.NewEntry(log.New())
+			entry.Time, entry.Level, entry.Message = goldenTime, log.InfoLevel, "m"
+			entry.Data = log.Fields{"at": attrTime}
+			out, err := tf.Format(entry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := tt.line + " INFO ▶ m || at={" + tt.attr + "}, \n"
+			if string(out) != want {
+				t.Errorf("got  %q\nwant %q", out, want)
+			}
+		})
+	}
+}
+
+func TestTimeValuesUTCOption(t *testing.T) {
+	var buf bytes.Buffer
+	opts := Default()
+	opts.Colored = false
+	opts.UTC = true
+	h, err := NewSLogHandler(&buf, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slog.New(h).Info("m", "at", attrTime.In(testZone))
+	if want := "at={2024-01-02T03:04:05Z}"; !strings.Contains(buf.String(), want) {
+		t.Errorf("got %q, want %q in it", buf.String(), want)
+	}
+}
+
+func TestTimeLocation(t *testing.T) {
+	if _, err := time.LoadLocation("Asia/Kolkata"); err != nil {
+		t.Skipf("no zone database: %v", err)
+	}
+	tests := []struct {
+		name string
+		set  func(*ZyLogOptions)
+		want string
+	}{
+		{"utc", func(o *ZyLogOptions) { o.UTC = true }, "2024-03-01T12:30:45Z"},
+		{"time location", func(o *ZyLogOptions) { o.TimeLocation = "Asia/Kolkata" }, "2024-03-01T18:00:45+05:30"},
+		{"time location over utc", func(o *ZyLogOptions) {
+			o.UTC = true
+			o.TimeLocation = "Asia/Kolkata"
+		}, "2024-03-01T18:00:45+05:30"},
+		{"location over time location", func(o *ZyLogOptions) {
+			o.Location = time.FixedZone("EST", -5*3600)
+			o.TimeLocation = "Asia/Kolkata"
+		}, "2024-03-01T07:30:45-05:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			opts := Default()
+			opts.Colored = false
+			tt.set(opts)
+			if err := opts.Validate(); err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			h, err := NewSLogHandler(&buf, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := slog.NewRecord(goldenTime, slog.LevelInfo, "m", 0)
+			if err := h.Handle(context.Background(), r); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Fields(buf.String())[0]; got != tt.want {
+				t.Errorf("timestamp %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The keys of the attributes (or logrus fields) added from the span context
+// found by the SpanContext option.
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+// SpanContextFunc finds the IDs of the trace and span active in ctx, e.g.
+// for OpenTelemetry:
+//
+//	func(ctx context.Context) (string, string, bool) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		return sc.TraceID().String(), sc.SpanID().String(), sc.IsValid()
+//	}
+//
+// zylog takes the IDs through a function, rather than calling the tracing
+// library itself, so as not to depend on one.
+type SpanContextFunc func(ctx context.Context) (traceID, spanID string, ok bool)
+
+// A logrus hook which adds the IDs of the span active in the entry's context
+// (see Entry.WithContext) to the entry.
+type spanHook struct {
+	ids SpanContextFunc
+}
+
+func (sh *spanHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (sh *spanHook) Fire(entry *log.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+	traceID, spanID, ok := sh.ids(entry.Context)
+	if !ok {
+		return nil
+	}
+	// As for stackHook, the fields are copied rather than added to.
+	data := make(log.Fields, len(entry.Data)+2)
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	data[TraceIDKey] = traceID
+	data[SpanIDKey] = spanID
+	entry.Data = data
+	return nil
+}
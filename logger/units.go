@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"log/slog"
+	"math"
+	"strconv"
+)
+
+// Quantity is an attribute value with a unit. In text output, quantities are
+// scaled for readability, e.g. 1048576 bytes is shown as 1.0MiB; in JSON
+// output the raw value is kept and the unit is added as a sibling attribute
+// whose key has a "_unit" suffix. An empty unit denotes a count.
+//
+// ReplaceAttr functions are given the raw value as a number, without the
+// unit; if they return it unchanged, it is shown with its unit as above.
+type Quantity struct {
+	Value float64
+	Unit  string
+}
+
+// Unit prefixes, by power.
+var (
+	siPrefixes     = []string{"", "k", "M", "G", "T", "P", "E"}
+	binaryPrefixes = []string{"", "Ki", "Mi", "Gi", "Ti", "Pi", "Ei"}
+)
+
+// The raw value of a quantity, as an slog value.
+func (q Quantity) slogValue() slog.Value {
+	if q.Value == math.Trunc(q.Value) && math.Abs(q.Value) < 1<<53 {
+		return slog.Int64Value(int64(q.Value))
+	}
+	return slog.Float64Value(q.Value)
+}
+
+// Render a quantity scaled by the largest prefix that leaves at least one
+// unit. Counts are always scaled with SI prefixes; other units use binary
+// prefixes unless si is set.
+func (q Quantity) scaled(si bool) string {
+	base, prefixes := 1024.0, binaryPrefixes
+	if si || q.Unit == "" {
+		base, prefixes = 1000, siPrefixes
+	}
+	v, i := q.Value, 0
+	for math.Abs(v) >= base && i < len(prefixes)-1 {
+		v /= base
+		i++
+	}
+	if i == 0 {
+		return strconv.FormatFloat(v, 'f', -1, 64) + q.Unit
+	}
+	return strconv.FormatFloat(v, 'f', 1, 64) + prefixes[i] + q.Unit
+}
+
+// If the attribute's value is a Quantity, apply ReplaceAttr to its raw value,
+// returning the resulting attribute and, if ReplaceAttr left it alone, the
+// quantity. Otherwise the attribute is simply passed to ReplaceAttr. Either
+// way, a value to be redacted is redacted first.
+func (h *SLogHandler) replaceQuantity(groups []string, a slog.Attr) (slog.Attr, *Quantity, bool) {
+	a = h.redactor.redact(groups, a)
+	q, isQuantity := a.Value.Any().(Quantity)
+	if a.Value.Kind() != slog.KindAny || !isQuantity {
+		a, ok := h.replace(groups, a)
+		return a, nil, ok
+	}
+	raw := slog.Attr{Key: a.Key, Value: q.slogValue()}
+	r, ok := h.replace(groups, raw)
+	if !ok {
+		return r, nil, false
+	}
+	if r.Key != raw.Key || !r.Value.Equal(raw.Value) {
+		return r, nil, true
+	}
+	return r, &q, true
+}
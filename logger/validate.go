@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"fmt"
+	"time"
+)
+
+// Validate checks the options for sanity, returning an error for the first
+// problem found: ErrLogLevel for an unknown level, ErrUnsupLogOutput
+// (wrapped) for an unknown output or file format, ErrUnsupLogger (wrapped)
+// for an unknown backend, or ErrInvalidOption (wrapped) for a bad value of
+// any other option, such as the format or the timestamp format. Both
+// SetupSlog and SetupLogRUs validate their options before doing anything
+// else.
+func (opts *ZyLogOptions) Validate() error {
+	if _, err := parseSlogLevel(string(opts.Level)); err != nil {
+		return err
+	}
+	for _, name := range opts.PadLevels {
+		if _, err := parseSlogLevel(name); err != nil {
+			return fmt.Errorf("%w: pad level %s", err, name)
+		}
+	}
+	if opts.Writer == nil && len(opts.Destinations) == 0 {
+		outputs := opts.Outputs
+		if len(outputs) == 0 {
+			outputs = []string{opts.Output}
+		}
+		for _, name := range outputs {
+			if err := validateOutput(name, opts); err != nil {
+				return err
+			}
+		}
+	}
+	if opts.StackTraceLevel != "" {
+		if _, err := parseSlogLevel(opts.StackTraceLevel); err != nil {
+			return fmt.Errorf("%w: stack trace level %s", err, opts.StackTraceLevel)
+		}
+	}
+	for pkg, name := range opts.PackageLevels {
+		if _, err := parseSlogLevel(name); err != nil {
+			return fmt.Errorf("%w: level %s for %s", err, name, pkg)
+		}
+	}
+	if opts.ErrorLevel != "" {
+		if _, err := parseSlogLevel(opts.ErrorLevel); err != nil {
+			return fmt.Errorf("%w: error level %s", err, opts.ErrorLevel)
+		}
+	}
+	for i, d := range opts.Destinations {
+		o := destinationOptions(opts, i)
+		if err := validateOutput(d.Output, o); err != nil {
+			return err
+		}
+		if _, err := fileFormat(o); err != nil {
+			return err
+		}
+	}
+	if opts.ErrorOutput != "" {
+		if err := validateOutput(opts.ErrorOutput, opts); err != nil {
+			return err
+		}
+	}
+	if _, err := fileFormat(opts); err != nil {
+		return err
+	}
+	switch opts.CallerFormat {
+	case "", CallerFunc, CallerFile, CallerFileFunc:
+	default:
+		return fmt.Errorf("%w: caller format %s", ErrInvalidOption, opts.CallerFormat)
+	}
+	if _, ok := timestampLayouts[opts.TimestampFormat]; !ok {
+		return fmt.Errorf("%w: timestamp format %s", ErrInvalidOption, opts.TimestampFormat)
+	}
+	if s := opts.Sampling; s != nil && (s.Initial < 0 || s.Thereafter < 0 || s.Tick < 0) {
+		return fmt.Errorf("%w: sampling %d/%d per %s", ErrInvalidOption, s.Initial, s.Thereafter, s.Tick)
+	}
+	if opts.RedactPattern != "" {
+		if _, err := compileRedactPattern(opts.RedactPattern); err != nil {
+			return err
+		}
+	}
+	if opts.Theme != "" {
+		if _, err := PresetColours(opts.Theme); err != nil {
+			return err
+		}
+	}
+	if err := validateRateLimits(opts.RateLimits); err != nil {
+		return err
+	}
+	if opts.ElapsedPrecision < 0 || opts.ElapsedPrecision > 9 {
+		return fmt.Errorf("%w: elapsed precision %d", ErrInvalidOption, opts.ElapsedPrecision)
+	}
+	if opts.TimeLocation != "" {
+		if _, err := time.LoadLocation(opts.TimeLocation); err != nil {
+			return fmt.Errorf("%w: time location %s", ErrInvalidOption, opts.TimeLocation)
+		}
+	}
+	if opts.CustomTimestampLayout != "" {
+		if err := validateLayout(opts.CustomTimestampLayout); err != nil {
+			return err
+		}
+	}
+	switch opts.Multiline {
+	case "", MultilineRaw, MultilineEscape, MultilineIndent:
+	default:
+		return fmt.Errorf("%w: multiline mode %s", ErrInvalidOption, opts.Multiline)
+	}
+	switch opts.Format {
+	case "", FormatZylog, FormatSlogText:
+	default:
+		return fmt.Errorf("%w: format %s", ErrInvalidOption, opts.Format)
+	}
+	if opts.Logger != Slog && opts.Logger != LogRUs {
+		return fmt.Errorf("%w: %s", ErrUnsupLogger, opts.Logger)
+	}
+	return nil
+}
+
+func validateOutput(name string, opts *ZyLogOptions) error {
+	switch name {
+	case "stdout", "stderr", "syslog":
+		return nil
+	case "filesystem":
+		if opts.File == "" {
+			return fmt.Errorf("%w: %s", ErrUnsupLogOutput, "filesystem without a File")
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupLogOutput, name)
+}
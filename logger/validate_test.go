@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("Default().Validate() = %v", err)
+	}
+	tests := []struct {
+		name   string
+		modify func(*ZyLogOptions)
+		want   error
+	}{
+		{"level", func(o *ZyLogOptions) { o.Level = "loud" }, ErrLogLevel},
+		{"empty level", func(o *ZyLogOptions) { o.Level = "" }, ErrLogLevel},
+		{"pad level", func(o *ZyLogOptions) { o.PadLevels = []string{"info", "loud"} }, ErrLogLevel},
+		{"stack trace level", func(o *ZyLogOptions) { o.StackTraceLevel = "loud" }, ErrLogLevel},
+		{"package level", func(o *ZyLogOptions) {
+			o.PackageLevels = map[string]string{"github.com/some/dep": "loud"}
+		}, ErrLogLevel},
+		{"error level", func(o *ZyLogOptions) { o.ErrorLevel = "loud" }, ErrLogLevel},
+		{"rate limit level", func(o *ZyLogOptions) { o.RateLimits = map[string]int{"loud": 10} }, ErrLogLevel},
+		{"output", func(o *ZyLogOptions) { o.Output = "printer" }, ErrUnsupLogOutput},
+		{"outputs", func(o *ZyLogOptions) { o.Outputs = []string{"stdout", "printer"} }, ErrUnsupLogOutput},
+		{"filesystem without a file", func(o *ZyLogOptions) { o.Output = "filesystem" }, ErrUnsupLogOutput},
+		{"destination output", func(o *ZyLogOptions) {
+			o.Destinations = []Destination{{Output: "printer"}}
+		}, ErrUnsupLogOutput},
+		{"destination without a file", func(o *ZyLogOptions) {
+			o.Destinations = []Destination{{Output: "filesystem"}}
+		}, ErrUnsupLogOutput},
+		{"destination file format", func(o *ZyLogOptions) {
+			o.Destinations = []Destination{{Output: "filesystem", File: "log.txt", FileFormat: "xml"}}
+		}, ErrUnsupLogOutput},
+		{"error output", func(o *ZyLogOptions) { o.ErrorOutput = "printer" }, ErrUnsupLogOutput},
+		{"error output without a file", func(o *ZyLogOptions) { o.ErrorOutput = "filesystem" }, ErrUnsupLogOutput},
+		{"file format", func(o *ZyLogOptions) { o.FileFormat = "xml" }, ErrUnsupLogOutput},
+		{"caller format", func(o *ZyLogOptions) { o.CallerFormat = "line" }, ErrInvalidOption},
+		{"timestamp format", func(o *ZyLogOptions) { o.TimestampFormat = "sundial" }, ErrInvalidOption},
+		{"negative sampling", func(o *ZyLogOptions) { o.Sampling = &Sampling{Initial: -1} }, ErrInvalidOption},
+		{"negative sampling tick", func(o *ZyLogOptions) { o.Sampling = &Sampling{Tick: -time.Second} }, ErrInvalidOption},
+		{"redact pattern", func(o *ZyLogOptions) { o.RedactPattern = "(" }, ErrInvalidOption},
+		{"theme", func(o *ZyLogOptions) { o.Theme = "paisley" }, ErrUnknownColour},
+		{"rate limit", func(o *ZyLogOptions) { o.RateLimits = map[string]int{"info": 0} }, ErrInvalidOption},
+		{"elapsed precision", func(o *ZyLogOptions) { o.ElapsedPrecision = 10 }, ErrInvalidOption},
+		{"negative elapsed precision", func(o *ZyLogOptions) { o.ElapsedPrecision = -1 }, ErrInvalidOption},
+		{"time location", func(o *ZyLogOptions) { o.TimeLocation = "Middle/Earth" }, ErrInvalidOption},
+		{"custom timestamp layout", func(o *ZyLogOptions) { o.CustomTimestampLayout = "no layout" }, ErrInvalidOption},
+		{"multiline", func(o *ZyLogOptions) { o.Multiline = "fold" }, ErrInvalidOption},
+		{"format", func(o *ZyLogOptions) { o.Format = "xml" }, ErrInvalidOption},
+		{"logger", func(o *ZyLogOptions) { o.Logger = LogRUs + 1 }, ErrUnsupLogger},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Default()
+			tt.modify(opts)
+			err := opts.Validate()
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+			// The kinds of error can be told apart.
+			for _, other := range []error{ErrLogLevel, ErrUnsupLogOutput, ErrUnsupLogger, ErrInvalidOption, ErrUnknownColour} {
+				if other != tt.want && errors.Is(err, other) {
+					t.Errorf("Validate() = %v, which is also %v", err, other)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateWriter(t *testing.T) {
+	// A Writer takes the place of the outputs, which aren't checked.
+	opts := Default()
+	opts.Output = "printer"
+	opts.Writer = &bytes.Buffer{}
+	if err := opts.Validate(); err != nil {
+		t.Errorf("Validate() = %v with a Writer", err)
+	}
+}
+
+func TestSetupValidates(t *testing.T) {
+	opts := Default()
+	opts.Level = "loud"
+	if _, err := SetupSlog(opts); !errors.Is(err, ErrLogLevel) {
+		t.Errorf("SetupSlog = %v, want ErrLogLevel", err)
+	}
+	opts.Logger = LogRUs
+	if err := SetupLogRUs(opts); !errors.Is(err, ErrLogLevel) {
+		t.Errorf("SetupLogRUs = %v, want ErrLogLevel", err)
+	}
+}
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// VisibleWidth returns the number of terminal columns taken by s: ANSI escape
+// sequences (such as colour codes) take none, combining marks and other
+// non-spacing characters take none, East Asian wide characters (CJK, Hangul,
+// full-width forms, and most emoji) take two, and everything else takes one.
+func VisibleWidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			i += escapeLen(s[i:])
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// Return the length of the ANSI escape sequence at the start of s: a CSI
+// sequence (ESC [ parameters final-byte), or just the ESC and the following
+// byte otherwise.
+func escapeLen(s string) int {
+	if len(s) < 2 || s[1] != '[' {
+		return min(len(s), 2)
+	}
+	for i := 2; i < len(s); i++ {
+		if s[i] >= 0x40 && s[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+func runeWidth(r rune) int {
+	switch {
+	case r == utf8.RuneError, unicode.IsControl(r):
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case isWide(r):
+		return 2
+	}
+	return 1
+}
+
+// The East Asian wide and full-width ranges, along with the emoji blocks.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115f},
+	{0x2e80, 0x303e},
+	{0x3041, 0x33ff},
+	{0x3400, 0x4dbf},
+	{0x4e00, 0x9fff},
+	{0xa000, 0xa4cf},
+	{0xac00, 0xd7a3},
+	{0xf900, 0xfaff},
+	{0xfe30, 0xfe4f},
+	{0xff00, 0xff60},
+	{0xffe0, 0xffe6},
+	{0x1f300, 0x1f64f},
+	{0x1f900, 0x1f9ff},
+	{0x20000, 0x3fffd},
+}
+
+func isWide(r rune) bool {
+	for _, rng := range wideRanges {
+		if r < rng[0] {
+			return false
+		}
+		if r <= rng[1] {
+			return true
+		}
+	}
+	return false
+}
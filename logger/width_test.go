@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+
+	"github.com/geomyidia/zylog/internal/paint"
+)
+
+func TestVisibleWidth(t *testing.T) {
+	p := paint.New(true)
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{"empty", "", 0},
+		{"ascii", "hello", 5},
+		{"coloured", "\x1b[31mhello\x1b[0m", 5},
+		{"combined colours", "\x1b[97;41;1mhello\x1b[0m", 5},
+		{"24-bit colour", "\x1b[38;2;255;136;0mhi\x1b[0m \x1b[48;5;236mthere\x1b[0m", 8},
+		{"painted", p.Paint("WARNING", color.FgHiYellow, color.BgBlue, color.Bold), 7},
+		{"nested", "\x1b[1m\x1b[32mab\x1b[0mc\x1b[0m", 3},
+		{"CJK", "日本語", 6},
+		{"coloured CJK", "\x1b[36m日本\x1b[0m語", 6},
+		{"mixed", "ok \x1b[93m完了\x1b[0m!", 8},
+		{"hangul", "한글", 4},
+		{"full-width", "ＡＢ", 4},
+		{"combining mark", "é", 1},
+		{"emoji", "🎉", 2},
+		{"arrow", " ▶ ", 3},
+		{"unterminated escape", "ab\x1b[31", 2},
+		{"lone escape", "ab\x1b", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VisibleWidth(tt.s); got != tt.want {
+				t.Errorf("VisibleWidth(%q) = %d, want %d", tt.s, got, tt.want)
+			}
+		})
+	}
+}
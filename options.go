@@ -0,0 +1,174 @@
+package zylog
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/geomyidia/zylog/logger"
+)
+
+// Option configures the logger created by New.
+type Option func(*builder) error
+
+// The options being built by New, along with the names of those which have
+// been given, so that conflicting options can be reported.
+type builder struct {
+	opts  logger.ZyLogOptions
+	given map[string]bool
+}
+
+// New sets up a logger with the zylog formatting, configured by the given
+// options, as SetupLogging does (so for slog, the logger is installed as the
+// slog default). Without options, the logger is configured as by
+// logger.Default. Invalid options, and options given more than once, are
+// reported as errors.
+func New(options ...Option) (*slog.Logger, error) {
+	opts, err := NewOptions(options...)
+	if err != nil {
+		return nil, err
+	}
+	return SetupLogging(opts)
+}
+
+// NewOptions returns the default options (see logger.Default), as modified by
+// the given options, for setting up logging later, e.g.
+//
+//	opts, err := zylog.NewOptions(zylog.WithLevel("debug"), zylog.WithColour(false))
+//
+// Invalid options, and options given more than once, are reported as errors.
+func NewOptions(options ...Option) (*logger.ZyLogOptions, error) {
+	b := &builder{
+		opts:  *logger.Default(),
+		given: map[string]bool{},
+	}
+	for _, opt := range options {
+		if err := opt(b); err != nil {
+			return nil, err
+		}
+	}
+	if err := b.opts.Validate(); err != nil {
+		return nil, err
+	}
+	return &b.opts, nil
+}
+
+// Record that the named option has been given, failing if it already was.
+func (b *builder) give(name string) error {
+	if b.given[name] {
+		return fmt.Errorf("%w: %s", logger.ErrConflictingOption, name)
+	}
+	b.given[name] = true
+	return nil
+}
+
+// WithLevel sets the minimum level logged, e.g. "debug".
+func WithLevel(level string) Option {
+	return func(b *builder) error {
+		if err := b.give("level"); err != nil {
+			return err
+		}
+		b.opts.Level = logger.Level(level)
+		return nil
+	}
+}
+
+// WithOutput sets the writer to which lines are written. It conflicts with
+// WithNamedOutput.
+func WithOutput(w io.Writer) Option {
+	return func(b *builder) error {
+		if err := b.give("output"); err != nil {
+			return err
+		}
+		if w == nil {
+			return fmt.Errorf("%w: nil writer", logger.ErrUnsupLogOutput)
+		}
+		b.opts.Writer = w
+		return nil
+	}
+}
+
+// WithNamedOutput sets the output by name: stdout, stderr, filesystem, or
+// syslog. It conflicts with WithOutput.
+func WithNamedOutput(name string) Option {
+	return func(b *builder) error {
+		if err := b.give("output"); err != nil {
+			return err
+		}
+		b.opts.Output = name
+		return nil
+	}
+}
+
+// WithLogger sets the backend, slog or logrus.
+func WithLogger(backend logger.Backend) Option {
+	return func(b *builder) error {
+		if err := b.give("logger"); err != nil {
+			return err
+		}
+		b.opts.Logger = backend
+		return nil
+	}
+}
+
+// WithColour sets whether lines are coloured.
+func WithColour(coloured bool) Option {
+	return func(b *builder) error {
+		if err := b.give("colour"); err != nil {
+			return err
+		}
+		b.opts.Colored = coloured
+		return nil
+	}
+}
+
+// WithCaller sets whether the calling function and line are logged.
+func WithCaller(caller bool) Option {
+	return func(b *builder) error {
+		if err := b.give("caller"); err != nil {
+			return err
+		}
+		b.opts.ReportCaller = caller
+		return nil
+	}
+}
+
+// WithTimestampFormat sets the form of timestamps, e.g.
+// logger.TimestampRFC3339Milli (see the TimestampFormat option).
+func WithTimestampFormat(format string) Option {
+	return func(b *builder) error {
+		if err := b.give("timestamp format"); err != nil {
+			return err
+		}
+		b.opts.TimestampFormat = format
+		return nil
+	}
+}
+
+// WithPadding sets the levels (e.g. "info", "warn", "error") whose names are
+// padded to the width of the widest of them, so that lines at those levels
+// align (see the PadLevels option).
+func WithPadding(levels ...string) Option {
+	return func(b *builder) error {
+		if err := b.give("padding"); err != nil {
+			return err
+		}
+		b.opts.PadLevels = levels
+		return nil
+	}
+}
+
+// WithColours sets the colour theme used when lines are coloured, e.g. a
+// modified logger.DefaultColours().
+func WithColours(colours *logger.Colours) Option {
+	return func(b *builder) error {
+		if err := b.give("colours"); err != nil {
+			return err
+		}
+		if colours == nil {
+			return fmt.Errorf("%w: nil colours", logger.ErrUnknownColour)
+		}
+		b.opts.Colours = colours
+		return nil
+	}
+}
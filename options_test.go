@@ -0,0 +1,58 @@
+package zylog
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/geomyidia/zylog/logger"
+)
+
+func TestNewOptions(t *testing.T) {
+	var buf bytes.Buffer
+	opts, err := NewOptions(WithLevel("debug"), WithColour(false), WithOutput(&buf), WithCaller(true))
+	if err != nil {
+		t.Fatalf("NewOptions: %v", err)
+	}
+	if opts.Level != "debug" || opts.Colored || opts.Writer != &buf || !opts.ReportCaller {
+		t.Errorf("options not applied: %+v", opts)
+	}
+	if opts.Output != logger.Default().Output {
+		t.Errorf("Output = %q, want the default", opts.Output)
+	}
+	colours := logger.LightColours()
+	opts, err = NewOptions(
+		WithTimestampFormat(logger.TimestampRFC3339Milli),
+		WithPadding("info", "warn"),
+		WithColours(colours))
+	if err != nil {
+		t.Fatalf("NewOptions: %v", err)
+	}
+	if opts.TimestampFormat != logger.TimestampRFC3339Milli || len(opts.PadLevels) != 2 || opts.Colours != colours {
+		t.Errorf("options not applied: %+v", opts)
+	}
+}
+
+func TestNewOptionsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		want    error
+	}{
+		{"unknown level", []Option{WithLevel("loud")}, logger.ErrLogLevel},
+		{"unknown output", []Option{WithNamedOutput("printer")}, logger.ErrUnsupLogOutput},
+		{"nil writer", []Option{WithOutput(nil)}, logger.ErrUnsupLogOutput},
+		{"level twice", []Option{WithLevel("info"), WithLevel("debug")}, logger.ErrConflictingOption},
+		{"unknown timestamp format", []Option{WithTimestampFormat("sundial")}, logger.ErrInvalidOption},
+		{"unknown padded level", []Option{WithPadding("info", "loud")}, logger.ErrLogLevel},
+		{"nil colours", []Option{WithColours(nil)}, logger.ErrUnknownColour},
+		{"two outputs", []Option{WithNamedOutput("stderr"), WithOutput(&bytes.Buffer{})}, logger.ErrConflictingOption},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewOptions(tt.options...); !errors.Is(err, tt.want) {
+				t.Errorf("NewOptions error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package zylog
+
+import (
+	"log/slog"
+
+	"github.com/geomyidia/zylog/logger"
+)
+
+// Bytes returns an attribute for a number of bytes, shown in text output
+// scaled with binary (or, with the SIUnits option, SI) prefixes, e.g.
+// size={1.0MiB}.
+func Bytes(key string, n int64) slog.Attr {
+	return Unit(key, float64(n), "B")
+}
+
+// Count returns an attribute for a count, shown in text output scaled with SI
+// prefixes, e.g. rows={1.5k}.
+func Count(key string, n int64) slog.Attr {
+	return Unit(key, float64(n), "")
+}
+
+// Unit returns an attribute for a value in the given unit. See
+// logger.Quantity for how such attributes are rendered.
+func Unit(key string, value float64, unit string) slog.Attr {
+	return slog.Any(key, logger.Quantity{Value: value, Unit: unit})
+}
@@ -0,0 +1,60 @@
+package zylog
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/geomyidia/zylog/logger"
+)
+
+// The module paths of zylog and of the dependencies whose versions
+// PrintVersions reports.
+const (
+	modulePath = "github.com/geomyidia/zylog"
+	logrusPath = "github.com/sirupsen/logrus"
+	colorPath  = "github.com/fatih/color"
+)
+
+// Version returns the version of zylog, e.g. "v1.2.0": the one set at build
+// time (see logger.Version), if any, or else the one recorded in the
+// program's build info; "N/A" if neither is known.
+func Version() string {
+	if logger.Version != "" {
+		return logger.Version
+	}
+	return moduleVersion(modulePath)
+}
+
+// PrintVersions prints the versions of zylog, of the logging libraries it
+// uses (logrus and fatih/color), and of Go, along with the build details set
+// at build time (see logger.BuildString), e.g. for bug reports.
+func PrintVersions() {
+	fmt.Printf("zylog version: %s\n", Version())
+	fmt.Printf("Build: %s\n", logger.BuildString())
+	fmt.Printf("logrus version: %s\n", moduleVersion(logrusPath))
+	fmt.Printf("fatih/color version: %s\n", moduleVersion(colorPath))
+	fmt.Printf("Go version: %s\n", runtime.Version())
+}
+
+// The version of the module with the given path in the program's build info;
+// "N/A" if it isn't there. zylog's own module, when it is the main one (as
+// for the demo), is usually "(devel)".
+func moduleVersion(path string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "N/A"
+	}
+	if info.Main.Path == path {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == path {
+			if dep.Replace != nil {
+				dep = dep.Replace
+			}
+			return dep.Version
+		}
+	}
+	return "N/A"
+}
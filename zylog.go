@@ -0,0 +1,75 @@
+package zylog
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/geomyidia/zylog/logger"
+)
+
+// SetupLogging sets up the backend selected by opts.Logger with the zylog
+// formatting. Unlike logger.SetupLogging, it never panics; bad options are
+// reported with the errors defined by the logger package, and an unknown
+// backend with logger.ErrUnsupLogger.
+//
+// For slog, the logger is installed as the slog default and returned. For
+// logrus, the logrus standard logger is configured and the returned logger is
+// nil.
+func SetupLogging(opts *logger.ZyLogOptions) (*slog.Logger, error) {
+	switch opts.Logger {
+	case logger.Slog:
+		return logger.SetupSlog(opts)
+	case logger.LogRUs:
+		return nil, logger.SetupLogRUs(opts)
+	}
+	return nil, fmt.Errorf("%w: %s", logger.ErrUnsupLogger, opts.Logger)
+}
+
+// IsDefault reports whether the slog default logger uses a zylog handler.
+func IsDefault() bool {
+	return logger.IsDefault()
+}
+
+// SetupFromEnv sets up logging as SetupLogging does, using the options given
+// by the environment (see logger.FromEnv).
+func SetupFromEnv() (*slog.Logger, error) {
+	opts, err := logger.FromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return SetupLogging(opts)
+}
+
+// SetupFromFile sets up logging as SetupLogging does, using the options read
+// from a JSON or YAML file (see logger.LoadFile).
+func SetupFromFile(path string) (*slog.Logger, error) {
+	opts, err := logger.LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return SetupLogging(opts)
+}
+
+// SetLevel changes the minimum level logged, e.g. to "debug", while logging
+// is in use (see logger.SetLevel).
+func SetLevel(level string) error {
+	return logger.SetLevel(level)
+}
+
+// GetLevel returns the minimum level logged, e.g. "info".
+func GetLevel() string {
+	return logger.GetLevel()
+}
+
+// Named returns a logger like l whose records carry the given name, shown as
+// a [name] segment after the level; naming a named logger joins the names
+// with dots (see logger.Named).
+func Named(l *slog.Logger, name string) *slog.Logger {
+	return logger.Named(l, name)
+}
+
+// ResetEpoch restarts the clock shown by elapsed timestamps (see
+// logger.TimestampElapsed) from now, e.g. at the start of each benchmark run.
+func ResetEpoch() {
+	logger.ResetEpoch()
+}